@@ -0,0 +1,77 @@
+/**
+ * Scripted Client Tests
+ *
+ * Run a real council and provider adapter against a scripted OpenCode
+ * client, so the whole call path is covered without API keys.
+ */
+
+import { describe, it, expect, vi, afterEach } from 'vitest'
+import { Council } from '../../core/council'
+import { ProviderAdapter } from '../../providers/adapter'
+import { ProviderError } from '../../providers/errors'
+import { createMockProviderConfig, createScriptedOpenCodeClient } from '../mocks/providers'
+
+describe('Council with a scripted client', () => {
+  afterEach(() => {
+    vi.unstubAllGlobals()
+  })
+
+  const setup = (script: Array<string | Error>) => {
+    const { client, requests } = createScriptedOpenCodeClient(script)
+    const council = new Council({ maxRounds: 2 }, new ProviderAdapter())
+    council.initialize(client)
+    council.addParticipant(createMockProviderConfig({ id: 'host', modelId: 'host-model' }), { isHost: true, name: 'Host' })
+    council.addParticipant(createMockProviderConfig({ id: 'guest', modelId: 'guest-model' }), { name: 'Guest' })
+    return { council, requests }
+  }
+
+  it('should send each speaker its prompt and record the scripted replies', async () => {
+    const { council, requests } = setup(['Opening thoughts', 'A reply'])
+
+    await council.startDiscussion('Tabs or spaces?')
+
+    expect(requests.map(r => r.modelID)).toEqual(['host-model', 'guest-model'])
+    expect(requests[0].prompt).toContain('Tabs or spaces?')
+    expect(requests[1].system).toContain('Host')
+    expect(council.getState().rounds[0].messages.map(m => m.content)).toEqual(['Opening thoughts', 'A reply'])
+  })
+
+  it('should surface a scripted failure as a failed participant', async () => {
+    const { council } = setup(['Opening thoughts', new ProviderError('Invalid API key', 'auth', 401)])
+
+    await council.startDiscussion('Tabs or spaces?')
+
+    const guest = council.participants.find(p => p.name === 'Guest')!
+    const notice = council.getState().rounds[0].messages.find(m => m.type === 'system')
+    expect(guest.status).toBe('error')
+    expect(notice?.metadata?.errorKind).toBe('auth')
+  })
+
+  it('should send requests through the client even when direct-only settings are set', async () => {
+    const fetch = vi.fn()
+    vi.stubGlobal('fetch', fetch)
+    const { client, requests } = createScriptedOpenCodeClient(['Opening thoughts', 'A reply'])
+    const council = new Council({ maxRounds: 1 }, new ProviderAdapter())
+    council.initialize(client)
+    council.addParticipant(createMockProviderConfig({
+      id: 'host',
+      modelId: 'host-model',
+      headers: { 'X-Team': 'platform' },
+      overrideHeaders: true,
+      proxy: 'http://proxy.corp:8080',
+    }), { isHost: true, name: 'Host' })
+    council.addParticipant(createMockProviderConfig({
+      id: 'guest',
+      modelId: 'guest-model',
+      maxTokens: 8192,
+      temperature: 0.2,
+      topP: 0.9,
+    }), { name: 'Guest' })
+
+    await council.startDiscussion('Tabs or spaces?')
+
+    expect(fetch).not.toHaveBeenCalled()
+    expect(requests.map(r => r.modelID)).toEqual(['host-model', 'guest-model'])
+    expect(council.getState().rounds[0].messages.map(m => m.content)).toEqual(['Opening thoughts', 'A reply'])
+  })
+})
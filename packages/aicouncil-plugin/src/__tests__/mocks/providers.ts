@@ -5,7 +5,7 @@
  */
 
 import type { Participant, ProviderConfig, Message } from '../../types'
-import type { ModelResponse } from '../../providers/adapter'
+import type { ModelResponse, OpencodeClient } from '../../providers/adapter'
 
 /**
  * Create a mock provider config
@@ -177,3 +177,42 @@ export function createMockOpenCodeClient(
     },
   }
 }
+
+/**
+ * A request received by a scripted OpenCode client
+ */
+export interface ScriptedRequest {
+  providerID?: string
+  modelID?: string
+  prompt: string
+  system?: string
+}
+
+/**
+ * Create an OpenCode client that answers with scripted replies in order
+ *
+ * Plug it into a real ProviderAdapter to exercise the council without API
+ * keys. An Error in the script is thrown for that call; once the script
+ * runs out, the last entry repeats. Every request is recorded.
+ */
+export function createScriptedOpenCodeClient(script: Array<string | Error>) {
+  const requests: ScriptedRequest[] = []
+
+  const client: OpencodeClient = {
+    session: {
+      prompt: async ({ body }) => {
+        const entry = script[Math.min(requests.length, script.length - 1)]
+        requests.push({
+          providerID: body?.model?.providerID,
+          modelID: body?.model?.modelID,
+          prompt: body?.parts.map(p => p.text).join('') ?? '',
+          system: body?.system?.map(p => p.text).join(''),
+        })
+        if (entry instanceof Error) throw entry
+        return { data: { parts: [{ type: 'text', text: entry ?? '' }] } }
+      },
+    },
+  }
+
+  return { client, requests }
+}
@@ -107,6 +107,9 @@ describe('Plugin Smoke Tests', () => {
       expect(result.tool.council_models).toBeDefined()
       expect(result.tool.council_next).toBeDefined()
       expect(result.tool.council_end).toBeDefined()
+      expect(result.tool.council_export).toBeDefined()
+      expect(result.tool.council_add).toBeDefined()
+      expect(result.tool.council_remove).toBeDefined()
     })
   })
 
@@ -0,0 +1,26 @@
+import { describe, it, expect } from 'vitest'
+import { isCommand, parseCommand } from './commands'
+
+describe('isCommand', () => {
+  it('should recognise a leading slash followed by a name', () => {
+    expect(isCommand('/done')).toBe(true)
+    expect(isCommand('  /rounds 3')).toBe(true)
+  })
+
+  it('should leave ordinary guidance and paths alone', () => {
+    expect(isCommand('Focus on cost')).toBe(false)
+    expect(isCommand('/ 2')).toBe(false)
+    expect(isCommand('See the /docs folder')).toBe(false)
+  })
+})
+
+describe('parseCommand', () => {
+  it('should split the name from its arguments', () => {
+    expect(parseCommand('/Kick  Test Provider 2')).toEqual({ name: 'kick', args: ['Test', 'Provider', '2'] })
+    expect(parseCommand('/done')).toEqual({ name: 'done', args: [] })
+  })
+
+  it('should return null for non-commands', () => {
+    expect(parseCommand('Keep going')).toBeNull()
+  })
+})
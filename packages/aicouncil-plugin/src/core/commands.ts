@@ -0,0 +1,52 @@
+/**
+ * Discussion Commands
+ *
+ * Slash commands that control a running discussion, e.g. `/rounds 8`
+ */
+
+/**
+ * A parsed slash command
+ */
+export interface ParsedCommand {
+  /** Command name, lowercased, without the slash */
+  name: string
+  args: string[]
+}
+
+/**
+ * Outcome of a discussion command
+ */
+export interface CommandResult {
+  ok: boolean
+  message: string
+}
+
+/**
+ * Usage of each supported command
+ */
+export const COMMAND_USAGE: Record<string, string> = {
+  done: '/done',
+  summary: '/summary',
+  kick: '/kick <name or ID>',
+  add: '/add <name or ID of a removed participant>',
+  rounds: '/rounds <number>',
+}
+
+/**
+ * Whether text is a slash command rather than discussion content
+ */
+export function isCommand(text: string): boolean {
+  return /^\/[a-z]/i.test(text.trim())
+}
+
+/**
+ * Split a slash command into its name and arguments
+ *
+ * @returns null when the text is not a command
+ */
+export function parseCommand(text: string): ParsedCommand | null {
+  if (!isCommand(text)) return null
+
+  const [name, ...args] = text.trim().slice(1).split(/\s+/)
+  return { name: name!.toLowerCase(), args }
+}
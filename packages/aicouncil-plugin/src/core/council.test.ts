@@ -1,6 +1,8 @@
 import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
 import { Council, getCouncil, resetCouncil } from './council'
 import { providerAdapter } from '../providers/adapter'
+import { ProviderError } from '../providers/errors'
+import { getLogLevel } from '../utils'
 import type { ProviderConfig } from '../types'
 
 // Mock the provider adapter
@@ -65,6 +67,16 @@ describe('Council', () => {
       expect(state.config.responseTimeout).toBe(60000)
       expect(state.config.locale).toBe('zh')
     })
+
+    it('should apply the log level, with verbose forcing debug', () => {
+      resetCouncil()
+      getCouncil({ logLevel: 'error' })
+      expect(getLogLevel()).toBe('error')
+
+      resetCouncil()
+      getCouncil({ logLevel: 'error', verbose: true })
+      expect(getLogLevel()).toBe('debug')
+    })
   })
 
   describe('addParticipant', () => {
@@ -119,6 +131,48 @@ describe('Council', () => {
     })
   })
 
+  describe('previewDiscussion', () => {
+    beforeEach(() => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant({ ...mockProvider2, systemPrompt: 'Be brief.' })
+    })
+
+    it('should list the first round\'s requests without calling any model', () => {
+      const preview = council.previewDiscussion('Test topic')
+
+      expect(providerAdapter.call).not.toHaveBeenCalled()
+      expect(preview.map(p => [p.name, p.isHost])).toEqual([
+        ['Test Provider 1', true],
+        ['Test Provider 2', false],
+      ])
+      expect(preview[0].prompt).toContain('Test topic')
+      expect(preview[1].systemPrompt).toContain('Be brief.')
+      expect(council.discussionStatus).toBe('idle')
+      expect(council.discussionTopic).toBe('')
+    })
+
+    it('should throw if there is no host', () => {
+      resetCouncil()
+      const noHostCouncil = getCouncil()
+      noHostCouncil.addParticipant(mockProvider1)
+      noHostCouncil.addParticipant(mockProvider2)
+
+      expect(() => noHostCouncil.previewDiscussion('Test topic')).toThrow()
+    })
+
+    it('should cap speakers preferring those the topic mentions', () => {
+      resetCouncil()
+      const cappedCouncil = getCouncil({ maxSpeakersPerRound: 1 })
+      cappedCouncil.addParticipant(mockProvider1, { isHost: true })
+      cappedCouncil.addParticipant(mockProvider2)
+      cappedCouncil.addParticipant({ ...mockProvider2, id: 'critic', name: 'Critic' })
+
+      const preview = cappedCouncil.previewDiscussion('@Critic, is this safe?')
+
+      expect(preview.map(p => p.name)).toEqual(['Test Provider 1', 'Critic'])
+    })
+  })
+
   describe('startDiscussion', () => {
     beforeEach(() => {
       council.addParticipant(mockProvider1, { isHost: true })
@@ -203,6 +257,41 @@ describe('Council', () => {
       await limitedCouncil.nextRound()
       expect(limitedCouncil.isComplete).toBe(true)
     })
+
+    it('should announce the round limit when it is reached', async () => {
+      resetCouncil()
+      const limitedCouncil = getCouncil({ maxRounds: 1 })
+      limitedCouncil.addParticipant(mockProvider1, { isHost: true })
+      limitedCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await limitedCouncil.startDiscussion('Test topic')
+      await limitedCouncil.nextRound()
+
+      const messages = limitedCouncil.getState().rounds[0].messages
+      expect(messages[messages.length - 1]).toMatchObject({
+        type: 'system',
+        content: 'The round limit of 1 has been reached; the discussion is closed.',
+      })
+    })
+
+    it('should not limit rounds when maxRounds is 0', async () => {
+      resetCouncil()
+      const openCouncil = getCouncil({ maxRounds: 0 })
+      openCouncil.addParticipant(mockProvider1, { isHost: true })
+      openCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await openCouncil.startDiscussion('Test topic')
+      for (let i = 0; i < 6; i++) {
+        await openCouncil.nextRound()
+      }
+
+      expect(openCouncil.totalRounds).toBe(7)
+      expect(openCouncil.isRunning).toBe(true)
+    })
   })
 
   describe('endDiscussion', () => {
@@ -227,6 +316,723 @@ describe('Council', () => {
     })
   })
 
+  describe('membership announcements', () => {
+    beforeEach(() => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+    })
+
+    it('should not announce participants added before the discussion starts', () => {
+      const messages = council.getState().rounds.flatMap(r => r.messages)
+      expect(messages).toHaveLength(0)
+    })
+
+    it('should announce a join as a system message without triggering a response', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      vi.mocked(providerAdapter.call).mockClear()
+
+      const handler = vi.fn()
+      council.on('message:new', handler)
+      council.addParticipant({ ...mockProvider2, id: 'late', name: 'Late Joiner' })
+
+      expect(handler).toHaveBeenCalledWith(
+        expect.objectContaining({
+          type: 'system',
+          content: 'Late Joiner joined the discussion',
+        })
+      )
+      expect(providerAdapter.call).not.toHaveBeenCalled()
+    })
+
+    it('should announce a leave', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+
+      const guest = council.participants.find(p => !p.isHost)!
+      council.removeParticipant(guest.id)
+
+      const messages = council.getState().rounds.flatMap(r => r.messages)
+      expect(messages[messages.length - 1]).toMatchObject({
+        type: 'system',
+        content: 'Test Provider 2 left the discussion',
+      })
+    })
+
+    it('should skip announcements when disabled', async () => {
+      resetCouncil()
+      const quietCouncil = getCouncil({ announceMembership: false })
+      quietCouncil.addParticipant(mockProvider1, { isHost: true })
+      quietCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await quietCouncil.startDiscussion('Test topic')
+      quietCouncil.addParticipant({ ...mockProvider2, id: 'late', name: 'Late Joiner' })
+
+      const systemMessages = quietCouncil.getState().rounds
+        .flatMap(r => r.messages)
+        .filter(m => m.type === 'system')
+      expect(systemMessages).toHaveLength(0)
+    })
+  })
+
+  describe('duplicate reply suppression', () => {
+    it('should suppress a participant repeating its previous reply', async () => {
+      resetCouncil()
+      const dedupCouncil = getCouncil({ duplicateReplyWindow: 3 })
+      dedupCouncil.addParticipant(mockProvider1, { isHost: true })
+      dedupCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Same text every time' })
+
+      const handler = vi.fn()
+      dedupCouncil.on('message:new', handler)
+
+      await dedupCouncil.startDiscussion('Test topic')
+      await dedupCouncil.nextRound()
+
+      const secondRound = dedupCouncil.getState().rounds[1]
+      expect(secondRound.messages.every(m => m.type === 'system')).toBe(true)
+      expect(secondRound.messages[0].content).toContain('repeated an earlier reply')
+      expect(handler).toHaveBeenCalledTimes(4)
+      expect(handler.mock.calls.slice(2).map(([m]) => m)).toEqual(secondRound.messages)
+    })
+
+    it('should keep repeated replies when disabled', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Same text every time' })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      await council.startDiscussion('Test topic')
+      await council.nextRound()
+
+      const secondRound = council.getState().rounds[1]
+      expect(secondRound.messages.every(m => m.type === 'assistant')).toBe(true)
+    })
+  })
+
+  describe('first-turn prompts', () => {
+    it('should post exactly one introduction per participant', async () => {
+      council.addParticipant(mockProvider1, { isHost: true, firstTurnPrompt: 'State your role.' })
+      council.addParticipant(mockProvider2, { firstTurnPrompt: 'Say which angle you will cover.' })
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+      await council.nextRound()
+
+      const intros = council.getState().rounds
+        .flatMap(r => r.messages)
+        .filter(m => m.metadata?.intro)
+      expect(intros.map(m => m.from)).toEqual(['Test Provider 1', 'Test Provider 2'])
+      expect(providerAdapter.call).toHaveBeenCalledWith(
+        expect.anything(),
+        expect.stringContaining('Say which angle you will cover.'),
+        expect.anything()
+      )
+    })
+
+    it('should not introduce participants without a first-turn prompt', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+
+      expect(providerAdapter.call).toHaveBeenCalledTimes(2)
+    })
+  })
+
+  describe('short response retry', () => {
+    it('should retry a too-short reply and keep the full one', async () => {
+      resetCouncil()
+      const strictCouncil = getCouncil({ minResponseLength: 10 })
+      strictCouncil.addParticipant(mockProvider1, { isHost: true })
+      strictCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'ok' })
+        .mockResolvedValue({ content: 'A full and substantive reply' })
+
+      await strictCouncil.startDiscussion('Test topic')
+
+      const messages = strictCouncil.getState().rounds[0].messages
+      expect(messages.map(m => m.content)).toEqual([
+        'A full and substantive reply',
+        'A full and substantive reply',
+      ])
+      expect(providerAdapter.call).toHaveBeenCalledTimes(3)
+      expect(providerAdapter.call).toHaveBeenNthCalledWith(
+        2,
+        expect.anything(),
+        expect.stringContaining('too brief'),
+        expect.anything()
+      )
+    })
+
+    it('should accept short replies when disabled', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'ok' })
+      await council.startDiscussion('Test topic')
+
+      expect(providerAdapter.call).toHaveBeenCalledTimes(2)
+    })
+  })
+
+  describe('coordinator', () => {
+    const mockProvider3: ProviderConfig = {
+      ...mockProvider2,
+      id: 'test-provider-3',
+      name: 'Test Provider 3',
+    }
+
+    beforeEach(() => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      council.addParticipant(mockProvider3)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+    })
+
+    it('should only ask the participants the coordinator selects', async () => {
+      const coordinator = vi.fn(({ candidates }) =>
+        candidates.filter((p: { name: string }) => p.name === 'Test Provider 3')
+      )
+      council.setCoordinator(coordinator)
+
+      await council.startDiscussion('Test topic')
+
+      const speakers = council.getState().rounds[0].messages.map(m => m.from)
+      expect(speakers).toEqual(['Test Provider 1', 'Test Provider 3'])
+      expect(coordinator).toHaveBeenCalledWith(
+        expect.objectContaining({ topic: 'Test topic', round: 1 })
+      )
+    })
+
+    it('should consult the coordinator after the host opens the round', async () => {
+      const coordinator = vi.fn(({ candidates }) => candidates)
+      council.setCoordinator(coordinator)
+
+      await council.startDiscussion('Test topic')
+
+      expect(coordinator.mock.calls[0][0].latestMessage).toMatchObject({ from: 'Test Provider 1', content: 'Test response' })
+    })
+
+    it('should count the coordinator\'s model calls in usage', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({
+        content: 'Test response',
+        usage: { inputTokens: 10, outputTokens: 5 },
+      })
+      council.setCoordinator(async ({ candidates, ask }) => {
+        await ask(council.participants[0], 'Who should speak?')
+        return candidates
+      })
+
+      await council.startDiscussion('Test topic')
+
+      const host = council.getUsage()!.participants.find(p => p.name === 'Test Provider 1')!
+      expect(host.calls).toBe(2)
+      expect(vi.mocked(providerAdapter.call).mock.calls[1][1]).toBe('Who should speak?')
+      expect(vi.mocked(providerAdapter.call).mock.calls[1][2]).toHaveProperty('timeout')
+    })
+
+    it('should hold only round replies to the response schema', async () => {
+      council.setCoordinator(async ({ candidates, ask }) => {
+        await ask(council.participants[0], 'Who should speak?')
+        return candidates
+      })
+
+      await council.startDiscussion('Test topic')
+
+      const calls = vi.mocked(providerAdapter.call).mock.calls
+      const moderatorCall = calls.find(call => call[1] === 'Who should speak?')!
+      expect(moderatorCall[2]).not.toHaveProperty('enforceSchema')
+      const replyCalls = calls.filter(call => call[1] !== 'Who should speak?')
+      expect(replyCalls).toHaveLength(3)
+      for (const call of replyCalls) expect(call[2]).toMatchObject({ enforceSchema: true })
+    })
+
+    it('should fall back to everyone when the coordinator throws', async () => {
+      const warnSpy = vi.spyOn(console, 'warn').mockImplementation(() => {})
+      council.setCoordinator(() => {
+        throw new Error('moderator reply has no JSON list')
+      })
+
+      await council.startDiscussion('Test topic')
+
+      expect(council.getState().rounds[0].messages).toHaveLength(3)
+      expect(warnSpy).toHaveBeenCalledWith('[aicouncil] warn: coordinator failed error="moderator reply has no JSON list"')
+      warnSpy.mockRestore()
+    })
+  })
+
+  describe('system messages in context', () => {
+    it('should leave system notices out of the context for models that opt out', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant({ ...mockProvider2, includeSystemMessages: false })
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+      council.addParticipant({ ...mockProvider2, id: 'late', name: 'Late Joiner' })
+      vi.mocked(providerAdapter.call).mockClear()
+      await council.nextRound()
+
+      const promptFor = (name: string) =>
+        vi.mocked(providerAdapter.call).mock.calls.find(([p]) => p.name === name)![1]
+      expect(promptFor('Test Provider 1')).toContain('Late Joiner joined the discussion')
+      expect(promptFor('Test Provider 2')).not.toContain('Late Joiner joined the discussion')
+      expect(promptFor('Test Provider 2')).toContain('[Test Provider 1]: Test response')
+    })
+
+    it('should trim the context to a model\'s message budget and say so', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant({ ...mockProvider2, maxContextMessages: 1 })
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+      vi.mocked(providerAdapter.call).mockClear()
+      await council.nextRound()
+
+      const promptFor = (name: string) =>
+        vi.mocked(providerAdapter.call).mock.calls.find(([p]) => p.name === name)![1]
+      expect(promptFor('Test Provider 2')).toContain('[earlier messages omitted]')
+      expect(promptFor('Test Provider 1')).not.toContain('[earlier messages omitted]')
+    })
+  })
+
+  describe('late joiners', () => {
+    const lateProvider: ProviderConfig = { ...mockProvider2, id: 'late', name: 'Late Joiner' }
+
+    const promptsFor = (name: string) =>
+      vi.mocked(providerAdapter.call).mock.calls.filter(([p]) => p.name === name).map(([, prompt]) => prompt)
+
+    it('should prime a late joiner with the discussion so far, once', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Early point' })
+
+      await council.startDiscussion('Test topic')
+      council.addParticipant(lateProvider)
+      await council.nextRound()
+      await council.nextRound()
+
+      const [first, second] = promptsFor('Late Joiner')
+      expect(first).toContain('You are joining a discussion that is already under way.')
+      expect(first).toContain('Participants: Test Provider 1, Test Provider 2, Late Joiner')
+      expect(first).toContain('[Test Provider 2]: Early point')
+      expect(second).not.toContain('already under way')
+    })
+
+    it('should use a configured primer', async () => {
+      resetCouncil()
+      council = getCouncil({ lateJoinPrimer: 'Catch up on {topic}:\n{history}' })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Early point' })
+
+      await council.startDiscussion('Test topic')
+      council.addParticipant(lateProvider)
+      await council.nextRound()
+
+      expect(promptsFor('Late Joiner')[0]).toMatch(/^Catch up on Test topic:\n\[Test Provider 1\]: Early point/)
+    })
+
+    it('should fit the primer\'s history to the late joiner\'s context budget', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'Opening point' })
+        .mockResolvedValue({ content: 'Later point' })
+
+      await council.startDiscussion('Test topic')
+      council.addParticipant({ ...lateProvider, maxContextMessages: 1 })
+      await council.nextRound()
+
+      const [first] = promptsFor('Late Joiner')
+      expect(first).toContain('[earlier messages omitted]')
+      expect(first).not.toContain('Opening point')
+    })
+
+    it('should not prime participants added before the discussion', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(lateProvider)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Early point' })
+
+      await council.startDiscussion('Test topic')
+
+      expect(promptsFor('Late Joiner')[0]).not.toContain('already under way')
+    })
+  })
+
+  describe('auto-continue', () => {
+    beforeEach(() => {
+      resetCouncil()
+      council = getCouncil({ maxContinuations: 2 })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+    })
+
+    it('should stitch a continuation onto a truncated reply', async () => {
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'The first half, ', finishReason: 'max_tokens' })
+        .mockResolvedValueOnce({ content: 'and the second half.', finishReason: 'end_turn' })
+        .mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const [hostMessage] = council.getState().rounds[0].messages
+      expect(hostMessage.content).toBe('The first half, and the second half.')
+      const continuePrompt = vi.mocked(providerAdapter.call).mock.calls[1][1]
+      expect(continuePrompt).toContain('cut off at the length limit')
+      expect(continuePrompt).toContain('The first half, ')
+    })
+
+    it('should keep the thinking and raw body of every part', async () => {
+      resetCouncil()
+      council = getCouncil({ maxContinuations: 2, keepRawResponses: true })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'Part one, ', finishReason: 'max_tokens', thinking: 'Start here.', raw: { id: 1 } })
+        .mockResolvedValueOnce({ content: 'part two.', finishReason: 'end_turn', thinking: 'Then finish.', raw: { id: 2 } })
+        .mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const [hostMessage] = council.getState().rounds[0].messages
+      expect(hostMessage.content).toBe('Part one, part two.')
+      expect(hostMessage.metadata?.thinking).toBe('Start here.\n\nThen finish.')
+      expect(hostMessage.metadata?.rawResponse).toEqual([{ id: 1 }, { id: 2 }])
+    })
+
+    it('should stop after maxContinuations', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'more ', finishReason: 'length' })
+
+      await council.startDiscussion('Test topic')
+
+      const [hostMessage] = council.getState().rounds[0].messages
+      expect(hostMessage.content).toBe('more more more ')
+    })
+  })
+
+  describe('turn mode', () => {
+    const mockProvider3: ProviderConfig = { ...mockProvider2, id: 'test-provider-3', name: 'Test Provider 3' }
+
+    const promptFor = (name: string) =>
+      vi.mocked(providerAdapter.call).mock.calls.find(([p]) => p.name === name)![1]
+
+    beforeEach(() => {
+      vi.mocked(providerAdapter.call).mockImplementation(async participant => ({
+        content: `Reply from ${participant.name}`,
+      }))
+    })
+
+    it('should show later speakers the earlier replies of the round by default', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      council.addParticipant(mockProvider3)
+
+      await council.startDiscussion('Test topic')
+
+      expect(promptFor('Test Provider 2')).toContain('[Test Provider 1]: Reply from Test Provider 1')
+      expect(promptFor('Test Provider 3')).toContain('[Test Provider 2]: Reply from Test Provider 2')
+    })
+
+    it('should ask participants together from the same context in parallel mode', async () => {
+      resetCouncil()
+      council = getCouncil({ turnMode: 'parallel' })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      council.addParticipant(mockProvider3)
+
+      await council.startDiscussion('Test topic')
+
+      expect(promptFor('Test Provider 3')).toContain('[Test Provider 1]: Reply from Test Provider 1')
+      expect(promptFor('Test Provider 3')).not.toContain('Reply from Test Provider 2')
+      expect(council.getState().rounds[0].messages).toHaveLength(3)
+    })
+  })
+
+  describe('commands', () => {
+    beforeEach(async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+    })
+
+    const notices = () =>
+      council.getState().rounds.flatMap(r => r.messages).filter(m => m.type === 'system')
+
+    it('should end the discussion on /done', async () => {
+      const result = await council.runCommand('/done')
+
+      expect(result.ok).toBe(true)
+      expect(council.isComplete).toBe(true)
+    })
+
+    it('should report a failed /summary instead of throwing', async () => {
+      vi.mocked(providerAdapter.call).mockRejectedValue(new Error('Service unavailable'))
+
+      const result = await council.runCommand('/summary')
+
+      expect(result).toEqual({ ok: false, message: 'Service unavailable' })
+      expect(notices().pop()?.metadata).toMatchObject({ command: 'summary', error: true })
+    })
+
+    it('should change the round limit on /rounds', async () => {
+      const result = await council.runCommand('/rounds 8')
+
+      expect(result.ok).toBe(true)
+      expect(council.getState().config.maxRounds).toBe(8)
+      expect(notices().pop()?.metadata?.roundLimit).toBe(8)
+    })
+
+    it('should remove a participant by name on /kick, but not the host', async () => {
+      expect((await council.runCommand('/kick test provider 2')).ok).toBe(true)
+      expect(council.participants.map(p => p.name)).toEqual(['Test Provider 1'])
+
+      const result = await council.runCommand('/kick Test Provider 1')
+      expect(result.ok).toBe(false)
+      expect(result.message).toContain('cannot be removed')
+    })
+
+    it('should bring a kicked participant back on /add, primed as a late joiner', async () => {
+      await council.runCommand('/kick Test Provider 2')
+      const result = await council.runCommand('/add test provider 2')
+
+      expect(result.ok).toBe(true)
+      expect(council.participants.map(p => p.name)).toEqual(['Test Provider 1', 'Test Provider 2'])
+
+      vi.mocked(providerAdapter.call).mockClear()
+      await council.nextRound()
+      const prompt = vi.mocked(providerAdapter.call).mock.calls.find(([p]) => p.name === 'Test Provider 2')![1]
+      expect(prompt).toContain('You are joining a discussion that is already under way.')
+    })
+
+    it('should restore a re-added participant\'s options and host role', async () => {
+      const host = council.participants.find(p => p.isHost)!
+      const guest = council.addParticipant(
+        { ...mockProvider2, id: 'guest', name: 'Guest' },
+        { name: 'Guest', firstTurnPrompt: 'State your role.' }
+      )
+
+      council.removeParticipant(guest.id)
+      council.removeParticipant(host.id)
+      expect((await council.runCommand('/add Guest')).ok).toBe(true)
+      expect((await council.runCommand(`/add ${host.name}`)).ok).toBe(true)
+
+      const guestAgain = council.participants.find(p => p.name === 'Guest')!
+      expect(guestAgain.firstTurnPrompt).toBe('State your role.')
+      expect(guestAgain.isHost).toBe(false)
+      expect(council.participants.find(p => p.name === host.name)!.isHost).toBe(true)
+    })
+
+    it('should not ask a participant removed earlier in the same round', async () => {
+      const third = council.addParticipant({ ...mockProvider2, id: 'third', name: 'Third' })
+      vi.mocked(providerAdapter.call).mockImplementation(async participant => {
+        if (participant.name === 'Test Provider 2') {
+          council.removeParticipant(third.id)
+        }
+        return { content: `Reply from ${participant.name}` }
+      })
+
+      await council.nextRound()
+
+      expect(vi.mocked(providerAdapter.call).mock.calls.some(([p]) => p.name === 'Third')).toBe(false)
+    })
+
+    it('should drop the reply of a participant removed while it was answering', async () => {
+      vi.mocked(providerAdapter.call).mockImplementation(async participant => {
+        if (participant.name === 'Test Provider 2') {
+          council.removeParticipant(participant.id)
+        }
+        return { content: `Reply from ${participant.name}` }
+      })
+
+      const round = await council.nextRound()
+
+      const replies = round!.messages.filter(m => m.type === 'assistant').map(m => m.from)
+      expect(replies).toEqual(['Test Provider 1'])
+      expect(round!.messages.some(m => m.metadata?.membership === 'leave')).toBe(true)
+    })
+
+    it('should refuse /add for someone who was never removed', async () => {
+      const result = await council.runCommand('/add Newcomer')
+
+      expect(result.ok).toBe(false)
+      expect(result.message).toContain('council_add')
+    })
+
+    it('should post a system message for unknown commands and bad arguments', async () => {
+      const unknown = await council.runCommand('/shrug')
+      const badRounds = await council.runCommand('/rounds many')
+
+      expect(unknown.ok).toBe(false)
+      expect(unknown.message).toContain('Unknown command /shrug')
+      expect(badRounds.message).toBe('Usage: /rounds <number>')
+      expect(notices().slice(-2).map(m => m.content)).toEqual([unknown.message, badRounds.message])
+      expect(council.currentRound).toBe(1)
+    })
+  })
+
+  describe('mention mode', () => {
+    beforeEach(() => {
+      resetCouncil()
+      council = getCouncil({ mentionMode: true })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      council.addParticipant({ ...mockProvider2, id: 'test-provider-3', name: 'Test Provider 3' })
+    })
+
+    it('should make no participant calls when nobody is mentioned', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Let us begin.' })
+
+      await council.startDiscussion('Test topic')
+
+      expect(providerAdapter.call).toHaveBeenCalledTimes(1)
+      expect(vi.mocked(providerAdapter.call).mock.calls[0][0].isHost).toBe(true)
+    })
+
+    it('should only call participants the host mentions', async () => {
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'What do you think, @Test Provider 3?' })
+        .mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const called = vi.mocked(providerAdapter.call).mock.calls.map(([p]) => p.name)
+      expect(called).toEqual(['Test Provider 1', 'Test Provider 3'])
+    })
+
+    it('should honour mentions in the topic for the first round', async () => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('@Test Provider 2, how should we cache this?')
+
+      const called = vi.mocked(providerAdapter.call).mock.calls.map(([p]) => p.name)
+      expect(called).toEqual(['Test Provider 1', 'Test Provider 2'])
+    })
+  })
+
+  describe('speaker limit', () => {
+    const extraProviders: ProviderConfig[] = [3, 4].map(n => ({
+      ...mockProvider2,
+      id: `test-provider-${n}`,
+      name: `Test Provider ${n}`,
+    }))
+
+    beforeEach(() => {
+      resetCouncil()
+      council = getCouncil({ maxSpeakersPerRound: 2 })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      extraProviders.forEach(p => council.addParticipant(p))
+    })
+
+    it('should let only the capped number of participants reply, mentions first', async () => {
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'Everyone weigh in, especially @Test Provider 4' })
+        .mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const messages = council.getState().rounds[0].messages
+      const speakers = messages.filter(m => m.type === 'assistant').map(m => m.from)
+      expect(speakers).toEqual(['Test Provider 1', 'Test Provider 4', 'Test Provider 2'])
+
+      const notice = messages.find(m => m.metadata?.skippedParticipants)
+      expect(notice?.content).toBe('Speaker limit reached; skipping Test Provider 3 this round')
+    })
+
+    it('should not post a notice when everyone fits', async () => {
+      resetCouncil()
+      council = getCouncil({ maxSpeakersPerRound: 5 })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const messages = council.getState().rounds[0].messages
+      expect(messages.some(m => m.type === 'system')).toBe(false)
+    })
+  })
+
+  describe('raw responses', () => {
+    const raw = { id: 'msg_1', model: 'test-model-1', stop_reason: 'end_turn' }
+
+    it('should attach the raw response to the message when enabled', async () => {
+      resetCouncil()
+      const auditCouncil = getCouncil({ keepRawResponses: true })
+      auditCouncil.addParticipant(mockProvider1, { isHost: true })
+      auditCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response', raw })
+      await auditCouncil.startDiscussion('Test topic')
+
+      const message = auditCouncil.getState().rounds[0].messages[0]
+      expect(message.metadata?.rawResponse).toEqual(raw)
+    })
+
+    it('should not keep raw responses by default', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response', raw })
+      await council.startDiscussion('Test topic')
+
+      const message = council.getState().rounds[0].messages[0]
+      expect(message.metadata?.rawResponse).toBeUndefined()
+    })
+  })
+
+  describe('response timeouts', () => {
+    beforeEach(() => {
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+    })
+
+    function timeoutFor(name: string): number | undefined {
+      const call = vi.mocked(providerAdapter.call).mock.calls.find(([p]) => p.name === name)
+      return call?.[2]?.timeout
+    }
+
+    it('should scale the default timeout for slow model families', async () => {
+      council.addParticipant({ ...mockProvider1, modelId: 'gemini-2.5-flash', name: 'Fast' }, { isHost: true })
+      council.addParticipant({ ...mockProvider2, modelId: 'o1-preview', name: 'Slow' })
+
+      await council.startDiscussion('Test topic')
+
+      expect(timeoutFor('Fast')).toBe(120000)
+      expect(timeoutFor('Slow')).toBe(360000)
+    })
+
+    it('should prefer a per-model timeout override', async () => {
+      council.addParticipant({ ...mockProvider1, name: 'Fast' }, { isHost: true })
+      council.addParticipant({ ...mockProvider2, modelId: 'o1', name: 'Slow', timeout: 45000 })
+
+      await council.startDiscussion('Test topic')
+
+      expect(timeoutFor('Slow')).toBe(45000)
+    })
+  })
+
+  describe('per-model system prompts', () => {
+    it('should append a model persona to its system prompt', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant({ ...mockProvider2, systemPrompt: '你是一个批判性的安全审查者' })
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+
+      const [hostCall, participantCall] = vi.mocked(providerAdapter.call).mock.calls
+      expect(hostCall[2]?.systemPrompt).not.toContain('安全审查者')
+      expect(participantCall[2]?.systemPrompt).toContain('multi-model AI discussion council')
+      expect(participantCall[2]?.systemPrompt).toMatch(/\n\n你是一个批判性的安全审查者$/)
+    })
+  })
+
   describe('pause and resume', () => {
     beforeEach(() => {
       council.addParticipant(mockProvider1, { isHost: true })
@@ -253,6 +1059,227 @@ describe('Council', () => {
     })
   })
 
+  describe('final summary', () => {
+    it('should ask the host for a consensus and add it as a summary message', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+
+      const handler = vi.fn()
+      council.on('summary:generated', handler)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Consensus reached' })
+      const summary = await council.summarize()
+
+      expect(summary?.type).toBe('summary')
+      expect(summary?.from).toBe('Test Provider 1')
+      expect(summary?.content).toBe('Consensus reached')
+      expect(council.summary).toBe(summary)
+      expect(handler).toHaveBeenCalledWith(summary)
+      const prompt = vi.mocked(providerAdapter.call).mock.calls.at(-1)![1]
+      expect(prompt).toContain('[Test Provider 2]: Test response')
+      expect(prompt).toContain('points of disagreement')
+    })
+
+    it('should not hold the summary to the response schema', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      await council.summarize()
+
+      expect(vi.mocked(providerAdapter.call).mock.calls.at(-1)![2]).not.toHaveProperty('enforceSchema')
+    })
+
+    it('should use the configured summarizer', async () => {
+      resetCouncil()
+      council = getCouncil({ summarizer: 'test-provider-2' })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      const summary = await council.summarize()
+
+      expect(summary?.from).toBe('Test Provider 2')
+    })
+
+    it('should fit the history to the summarizer\'s context budget', async () => {
+      council.addParticipant({ ...mockProvider1, maxContextTokens: 10 }, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'Opening point' })
+        .mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      await council.summarize()
+
+      const prompt = vi.mocked(providerAdapter.call).mock.calls.at(-1)![1]
+      expect(prompt).toContain('[earlier messages omitted]')
+      expect(prompt).not.toContain('Opening point')
+      expect(prompt).toContain('[Test Provider 2]: Test response')
+    })
+
+    it('should return null before anyone has spoken', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      expect(await council.summarize()).toBeNull()
+      expect(providerAdapter.call).not.toHaveBeenCalled()
+    })
+
+    it('should summarize when the round limit ends the discussion', async () => {
+      resetCouncil()
+      council = getCouncil({ maxRounds: 1, summarizeOnEnd: true })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Consensus reached' })
+      await council.nextRound()
+
+      expect(council.isComplete).toBe(true)
+      expect(council.summary?.content).toBe('Consensus reached')
+    })
+
+    it('should note a failed summary and still end', async () => {
+      resetCouncil()
+      council = getCouncil({ summarizeOnEnd: true })
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+      vi.mocked(providerAdapter.call).mockRejectedValue(new Error('overloaded'))
+      await council.endDiscussion()
+
+      const messages = council.getState().rounds.flatMap(r => r.messages)
+      expect(messages.at(-1)?.content).toBe('Could not generate the final summary: overloaded')
+      expect(council.discussionStatus).toBe('completed')
+    })
+  })
+
+  describe('reply metadata', () => {
+    it('should link a reply to the message that mentioned its author', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'What do you think, @Test Provider 2?' })
+        .mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const [hostReply, reply] = council.getState().rounds[0].messages
+      expect(reply.metadata?.replyTo).toBe(hostReply.id)
+      expect(hostReply.metadata).not.toHaveProperty('replyTo')
+
+      await council.nextRound()
+      expect(council.getState().rounds[1].messages[1].metadata).not.toHaveProperty('replyTo')
+    })
+
+    it('should record the provider, model and latency of each reply', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+      vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Test response' })
+
+      await council.startDiscussion('Test topic')
+
+      const [hostReply, reply] = council.getState().rounds[0].messages
+      expect(hostReply.metadata).toMatchObject({ provider: 'test-provider-1', model: 'test-model-1', isHost: true })
+      expect(reply.metadata).toMatchObject({ provider: 'test-provider-2', model: 'test-model-2', isHost: false })
+      expect(reply.metadata?.latencyMs).toBeGreaterThanOrEqual(0)
+      expect(reply.metadata).not.toHaveProperty('usage')
+    })
+  })
+
+  describe('usage', () => {
+    it('should total tokens per participant and record them on messages', async () => {
+      council.addParticipant({ ...mockProvider1, pricing: { input: 3, output: 15 } }, { isHost: true })
+      council.addParticipant({ ...mockProvider2, pricing: { input: 1, output: 5 } })
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({
+        content: 'Test response',
+        usage: { inputTokens: 1000, outputTokens: 200 },
+      })
+      await council.startDiscussion('Test topic')
+      await council.nextRound()
+
+      const usage = council.getUsage()!
+      expect(usage.participants.map(p => [p.name, p.inputTokens, p.outputTokens, p.calls])).toEqual([
+        ['Test Provider 1', 2000, 400, 2],
+        ['Test Provider 2', 2000, 400, 2],
+      ])
+      expect(usage.participants[0].estimatedCost).toBeCloseTo(0.012)
+      expect(usage.total).toMatchObject({ inputTokens: 4000, outputTokens: 800, calls: 4 })
+      expect(usage.total.estimatedCost).toBeCloseTo(0.016)
+
+      const message = council.getState().rounds[0].messages[0]
+      expect(message.metadata?.usage).toEqual({ inputTokens: 1000, outputTokens: 200 })
+    })
+
+    it('should keep the usage of removed and re-added participants', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      const guest = council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({
+        content: 'Test response',
+        usage: { inputTokens: 10, outputTokens: 5 },
+      })
+      await council.startDiscussion('Test topic')
+
+      council.removeParticipant(guest.id)
+      expect(council.getUsage()!.participants.map(p => [p.name, p.calls])).toEqual([
+        ['Test Provider 1', 1],
+        ['Test Provider 2', 1],
+      ])
+      expect(council.getUsage()!.total.calls).toBe(2)
+
+      await council.runCommand('/add Test Provider 2')
+      await council.nextRound()
+
+      const usage = council.getUsage()!
+      expect(usage.participants.map(p => [p.name, p.calls])).toEqual([
+        ['Test Provider 1', 2],
+        ['Test Provider 2', 2],
+      ])
+      expect(usage.total).toMatchObject({ inputTokens: 40, outputTokens: 20, calls: 4 })
+    })
+
+    it('should report usage as unavailable when a reply has no token counts', async () => {
+      council.addParticipant(mockProvider1, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call)
+        .mockResolvedValueOnce({ content: 'Test response', usage: { inputTokens: 10, outputTokens: 5 } })
+        .mockResolvedValue({ content: 'Test response' })
+      await council.startDiscussion('Test topic')
+
+      expect(council.getUsage()).toBeUndefined()
+
+      council.reset()
+      expect(council.getUsage()).toEqual({
+        participants: [],
+        total: { inputTokens: 0, outputTokens: 0, calls: 0 },
+      })
+    })
+
+    it('should leave out the total cost when a model has no pricing', async () => {
+      council.addParticipant({ ...mockProvider1, pricing: { input: 3, output: 15 } }, { isHost: true })
+      council.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockResolvedValue({
+        content: 'Test response',
+        usage: { inputTokens: 10, outputTokens: 10 },
+      })
+      await council.startDiscussion('Test topic')
+
+      expect(council.getUsage()!.total.estimatedCost).toBeUndefined()
+    })
+  })
+
   describe('reset', () => {
     it('should reset council to initial state', async () => {
       council.addParticipant(mockProvider1, { isHost: true })
@@ -377,6 +1404,50 @@ describe('Council', () => {
       expect(errorHandler).toHaveBeenCalled()
     })
 
+    it('should point at the API key when a provider rejects it', async () => {
+      vi.mocked(providerAdapter.call).mockImplementation(async participant => {
+        if (participant.isHost) return { content: 'Host response' }
+        throw new ProviderError('Kimi API error: 401 - invalid x-api-key', 'auth', 401)
+      })
+
+      await council.startDiscussion('Test topic')
+
+      const notice = council.getState().rounds[0].messages.find(m => m.metadata?.error)
+      expect(notice?.content).toBe(
+        'Test Provider 2 rejected its API key: Kimi API error: 401 - invalid x-api-key. Check the key configured for this model.'
+      )
+      expect(notice?.metadata?.errorKind).toBe('auth')
+    })
+
+    it('should post a notice when every participant fails', async () => {
+      vi.mocked(providerAdapter.call).mockImplementation(async participant => {
+        if (participant.isHost) return { content: 'Host response' }
+        throw new Error('API Error')
+      })
+
+      await council.startDiscussion('Test topic')
+
+      const messages = council.getState().rounds[0].messages
+      expect(messages[messages.length - 1]).toMatchObject({
+        type: 'system',
+        metadata: { allParticipantsFailed: true },
+      })
+      expect(council.isRunning).toBe(true)
+    })
+
+    it('should end the discussion when configured to', async () => {
+      resetCouncil()
+      const strictCouncil = getCouncil({ onAllParticipantsFailed: 'end' })
+      strictCouncil.addParticipant(mockProvider1, { isHost: true })
+      strictCouncil.addParticipant(mockProvider2)
+
+      vi.mocked(providerAdapter.call).mockRejectedValue(new Error('API Error'))
+
+      await strictCouncil.startDiscussion('Test topic')
+
+      expect(strictCouncil.isComplete).toBe(true)
+    })
+
     it('should update participant status on error', async () => {
       vi.mocked(providerAdapter.call).mockRejectedValue(new Error('API Error'))
 
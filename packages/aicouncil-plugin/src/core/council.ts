@@ -9,17 +9,33 @@ import type {
   DiscussionStatus,
   DiscussionConfig,
   Participant,
+  ParticipantOptions,
   Message,
   Round,
   CouncilCallbacks,
+  Coordinator,
   ProviderConfig,
+  UsageReport,
+  UsageTotals,
+  RequestPreview,
+  ReplyMetadata,
   DEFAULT_CONFIG,
 } from '../types'
 import { ParticipantManager } from './participant'
 import { RoundManager } from './round'
-import { providerAdapter, type OpencodeClient } from '../providers/adapter'
-import { t, setLocale } from '../i18n'
-import { generateId, createEventEmitter } from '../utils'
+import {
+  providerAdapter,
+  type OpencodeClient,
+  type ModelCallOptions,
+  type ModelCaller,
+  type ModelResponse,
+} from '../providers/adapter'
+import { ProviderError } from '../providers/errors'
+import { setRequestsPerMinute } from '../providers/rate-limit'
+import { isMentioned } from './mentions'
+import { parseCommand, COMMAND_USAGE, type CommandResult } from './commands'
+import { t, setLocale, interpolate } from '../i18n'
+import { generateId, createEventEmitter, hashString, redactSecrets, logger, setLogLevel } from '../utils'
 
 /**
  * Council events
@@ -29,13 +45,35 @@ type CouncilEvents = {
   'message:new': [Message]
   'participant:thinking': [Participant]
   'participant:response': [Participant, string]
+  'participant:delta': [Participant, string]
+  'participant:delta-reset': [Participant]
   'participant:error': [Participant, Error]
   'round:start': [Round]
   'round:complete': [Round]
   'discussion:start': [DiscussionState]
   'discussion:end': [DiscussionState]
+  'summary:generated': [Message]
 } & Record<string, unknown[]>
 
+/**
+ * Timeout multipliers for model families that routinely take longer
+ * than the council's default response timeout
+ */
+const MODEL_TIMEOUT_SCALES: Array<[RegExp, number]> = [
+  [/^(o1|o3|o4)(-|$)/i, 3],
+  [/reason|thinking/i, 3],
+  [/opus/i, 2],
+]
+
+/**
+ * Finish reasons providers report when a reply hit the token limit
+ */
+const TRUNCATED_FINISH_REASONS = new Set(['max_tokens', 'length', 'MAX_TOKENS'])
+
+function isTruncated(finishReason: string | undefined): boolean {
+  return finishReason !== undefined && TRUNCATED_FINISH_REASONS.has(finishReason)
+}
+
 /**
  * Council class - main orchestrator
  */
@@ -49,47 +87,123 @@ export class Council {
   private events = createEventEmitter<CouncilEvents>()
   private startedAt: Date | null = null
   private endedAt: Date | null = null
+  private replyHashes = new Map<string, string[]>()
+  private introduced = new Set<string>()
+  private lateJoiners = new Set<string>()
+  private finalSummary: Message | null = null
+  private usage = new Map<string, UsageTotals>()
+  /** Set once a call comes back without token counts, leaving the totals incomplete */
+  private usageUnavailable = false
+  private coordinator: Coordinator | null = null
+  private adapter: ModelCaller
+  private removed = new Map<string, { participant: Participant; options: ParticipantOptions }>()
 
-  constructor(config: Partial<DiscussionConfig> = {}) {
+  /**
+   * @param adapter - Sends model calls; defaults to the shared provider adapter
+   */
+  constructor(config: Partial<DiscussionConfig> = {}, adapter: ModelCaller = providerAdapter) {
     this.id = generateId()
+    this.adapter = adapter
     this.config = {
       maxRounds: config.maxRounds ?? 5,
       responseTimeout: config.responseTimeout ?? 120000,
       autoSummarize: config.autoSummarize ?? false,
       locale: config.locale ?? 'en',
+      logLevel: config.logLevel ?? 'info',
+      verbose: config.verbose ?? false,
+      requestsPerMinute: config.requestsPerMinute ?? 0,
+      announceMembership: config.announceMembership ?? true,
+      duplicateReplyWindow: config.duplicateReplyWindow ?? 0,
+      minResponseLength: config.minResponseLength ?? 0,
+      onAllParticipantsFailed: config.onAllParticipantsFailed ?? 'continue',
+      keepRawResponses: config.keepRawResponses ?? false,
+      showThinking: config.showThinking ?? false,
+      maxSpeakersPerRound: config.maxSpeakersPerRound ?? 0,
+      lateJoinPrimer: config.lateJoinPrimer ?? '',
+      mentionMode: config.mentionMode ?? false,
+      turnMode: config.turnMode ?? 'sequential',
+      maxContinuations: config.maxContinuations ?? 0,
+      summarizeOnEnd: config.summarizeOnEnd ?? false,
+      summarizer: config.summarizer ?? '',
     }
     this.participantManager = new ParticipantManager()
     this.roundManager = new RoundManager()
 
     // Set locale
     setLocale(this.config.locale)
+
+    // Verbose forces debug diagnostics
+    setLogLevel(this.config.verbose ? 'debug' : this.config.logLevel)
+
+    // Rate limits are per provider account, shared across the council
+    setRequestsPerMinute(this.config.requestsPerMinute)
   }
 
   /**
    * Initialize with OpenCode client
    */
   initialize(client: OpencodeClient): void {
-    providerAdapter.setClient(client)
+    this.adapter.setClient(client)
   }
 
   /**
    * Add a participant to the council
    */
-  addParticipant(provider: ProviderConfig, options?: { isHost?: boolean; name?: string }): Participant {
+  addParticipant(provider: ProviderConfig, options?: ParticipantOptions): Participant {
     const participant = this.participantManager.add(provider, options)
+    if (this.status === 'running' || this.status === 'paused') {
+      this.lateJoiners.add(participant.id)
+    }
+    this.announceMembership(participant, 'join')
     this.emitStateChange()
     return participant
   }
 
   /**
    * Remove a participant from the council
+   *
+   * Mid-discussion the departure is announced, and a reply the participant
+   * still has in flight is dropped when it arrives.
    */
   removeParticipant(participantId: string): boolean {
+    const participant = this.participantManager.get(participantId)
     const result = this.participantManager.remove(participantId)
+    this.lateJoiners.delete(participantId)
+    if (result && participant) {
+      this.removed.set(participant.id, {
+        participant,
+        options: {
+          name: participant.name,
+          isHost: participant.isHost,
+          firstTurnPrompt: participant.firstTurnPrompt,
+        },
+      })
+      this.announceMembership(participant, 'leave')
+    }
     this.emitStateChange()
     return result
   }
 
+  /**
+   * Post a system message when a participant joins or leaves mid-discussion.
+   * System messages are context only and never trigger a response.
+   */
+  private announceMembership(participant: Participant, change: 'join' | 'leave'): void {
+    if (!this.config.announceMembership) return
+    if (this.status !== 'running' && this.status !== 'paused') return
+
+    const message = this.roundManager.addMessage(
+      participant.name,
+      t(change === 'join' ? 'participant.joined' : 'participant.left', { name: participant.name }),
+      'system',
+      { participantId: participant.id, membership: change }
+    )
+
+    if (message) {
+      this.events.emit('message:new', message)
+    }
+  }
+
   /**
    * Set a participant as host
    */
@@ -99,6 +213,14 @@ export class Council {
     return result
   }
 
+  /**
+   * Set a coordinator that chooses which participants respond each round.
+   * Pass null to let every participant respond.
+   */
+  setCoordinator(coordinator: Coordinator | null): void {
+    this.coordinator = coordinator
+  }
+
   /**
    * Get current state
    */
@@ -143,6 +265,58 @@ export class Council {
     await this.runRound()
   }
 
+  /**
+   * Preview the requests that starting a discussion on `topic` would send
+   * in its first round, without calling any model or changing state.
+   *
+   * The coordinator is not consulted, since it may call a model itself;
+   * mention mode and the speaker cap are applied from the topic alone.
+   */
+  previewDiscussion(topic: string): RequestPreview[] {
+    if (this.participantManager.count < 2) {
+      throw new Error(t('setup.minModelsRequired'))
+    }
+
+    const host = this.participantManager.getHost()
+    if (!host) {
+      throw new Error(t('setup.hostRequired'))
+    }
+
+    const previousTopic = this.topic
+    this.topic = topic
+    try {
+      const preview = (participant: Participant, prompt: string, intro = false): RequestPreview => ({
+        participantId: participant.id,
+        name: participant.name,
+        isHost: participant.isHost,
+        intro,
+        systemPrompt: this.systemPromptFor(participant, participant.isHost),
+        prompt,
+      })
+
+      const intros = [host, ...this.participantManager.getNonHost()]
+        .filter(p => p.firstTurnPrompt && !this.introduced.has(p.id))
+        .map(p => preview(p, t('prompts.introPrompt', { topic, instruction: p.firstTurnPrompt! }), true))
+
+      let speakers = this.participantManager.getNonHost()
+      if (this.config.mentionMode) {
+        speakers = speakers.filter(p => this.mentions(topic, p))
+      }
+      if (this.config.maxSpeakersPerRound > 0) {
+        speakers = this.mentionedFirst(speakers, topic).slice(0, this.config.maxSpeakersPerRound)
+      }
+
+      const round = this.roundManager.totalRounds + 1
+      return [
+        ...intros,
+        preview(host, this.roundPrompt(host, round)),
+        ...speakers.map(p => preview(p, this.roundPrompt(p, round))),
+      ]
+    } finally {
+      this.topic = previousTopic
+    }
+  }
+
   /**
    * Run a single round of discussion
    */
@@ -151,8 +325,18 @@ export class Council {
       return null
     }
 
-    // Check max rounds
-    if (this.roundManager.totalRounds >= this.config.maxRounds) {
+    // Check max rounds (0 means unlimited)
+    const { maxRounds } = this.config
+    if (maxRounds > 0 && this.roundManager.totalRounds >= maxRounds) {
+      const notice = this.roundManager.addMessage(
+        t('messages.systemMessage'),
+        t('discussion.roundLimitReached', { rounds: maxRounds }),
+        'system',
+        { roundLimit: maxRounds }
+      )
+      if (notice) {
+        this.events.emit('message:new', notice)
+      }
       await this.endDiscussion()
       return null
     }
@@ -162,26 +346,58 @@ export class Council {
     this.events.emit('round:start', round)
     this.emitStateChange()
 
-    // Get context from previous rounds
-    const context = this.roundManager.getPreviousContext()
-
-    // Build prompt for this round
-    const roundPrompt = t('prompts.roundStartPrompt', {
-      round: round.number.toString(),
-      topic: this.topic,
-      context,
-    })
+    const promptFor = (participant: Participant) => this.roundPrompt(participant, round.number)
 
-    // Get host and participants
     const host = this.participantManager.getHost()!
-    const participants = this.participantManager.getNonHost()
+
+    // Participants with a first-turn prompt introduce themselves once
+    await this.runIntroductions()
 
     // Host opens the round
-    await this.getParticipantResponse(host, roundPrompt, true)
+    await this.getParticipantResponse(host, promptFor(host), true)
+
+    // The coordinator picks speakers with the host's opening in view
+    const selected = await this.selectSpeakers(round.number)
+    const participants = this.capSpeakers(this.filterByMention(selected, host, round.number), host)
 
-    // Each participant responds
-    for (const participant of participants) {
-      await this.getParticipantResponse(participant, roundPrompt, false)
+    const participantPrompt = (participant: Participant) => {
+      // Late joiners get the discussion so far before their first turn
+      const prompt = this.lateJoiners.has(participant.id)
+        ? `${this.lateJoinPrimer(participant)}\n\n${promptFor(participant)}`
+        : promptFor(participant)
+      this.lateJoiners.delete(participant.id)
+      return prompt
+    }
+
+    // Each participant responds: in turn, so later speakers see earlier
+    // replies, or all at once from the same context
+    let results: boolean[]
+    if (this.config.turnMode === 'parallel') {
+      const prompts = participants.map(participantPrompt)
+      results = await Promise.all(
+        participants.map((participant, i) => this.getParticipantResponse(participant, prompts[i], false))
+      )
+    } else {
+      results = []
+      for (const participant of participants) {
+        // Skip anyone removed while earlier speakers were answering
+        if (!this.participantManager.get(participant.id)) continue
+        results.push(await this.getParticipantResponse(participant, participantPrompt(participant), false))
+      }
+    }
+    const failures = results.filter(ok => !ok).length
+
+    const allFailed = results.length > 0 && failures === results.length
+    if (allFailed) {
+      const notice = this.roundManager.addMessage(
+        t('messages.systemMessage'),
+        t('errors.allParticipantsFailed'),
+        'system',
+        { allParticipantsFailed: true }
+      )
+      if (notice) {
+        this.events.emit('message:new', notice)
+      }
     }
 
     // Complete the round
@@ -190,73 +406,448 @@ export class Council {
       this.events.emit('round:complete', completedRound)
     }
 
+    if (allFailed && this.config.onAllParticipantsFailed === 'end') {
+      await this.endDiscussion()
+    }
+
     this.emitStateChange()
     return completedRound
   }
 
+  /**
+   * Build a speaker's prompt from the context so far, leaving out system
+   * notices for models that opt out of them
+   */
+  private roundPrompt(participant: Participant, roundNumber: number): string {
+    return t('prompts.roundStartPrompt', {
+      round: roundNumber.toString(),
+      topic: this.topic,
+      context: this.roundManager.getPreviousContext(participant.provider.maxContextMessages ?? 10, {
+        includeSystem: participant.provider.includeSystemMessages !== false,
+        maxTokens: participant.provider.maxContextTokens,
+        omittedNote: t('messages.earlierOmitted'),
+      }),
+    })
+  }
+
+  /**
+   * Build a participant's system prompt: its role, then its own instructions
+   */
+  private systemPromptFor(participant: Participant, isHost: boolean): string {
+    const rolePrompt = isHost
+      ? t('prompts.hostSystemPrompt', {
+          participants: this.participantManager.getParticipantNames(true),
+          topic: this.topic,
+        })
+      : t('prompts.participantSystemPrompt', {
+          host: this.participantManager.getHost()?.name ?? '',
+          participants: this.participantManager.getParticipantNames(true),
+          topic: this.topic,
+        })
+    return participant.provider.systemPrompt
+      ? `${rolePrompt}\n\n${participant.provider.systemPrompt}`
+      : rolePrompt
+  }
+
+  /**
+   * Resolve the non-host participants for a round via the coordinator.
+   * Falls back to everyone if there is no coordinator or it fails.
+   */
+  private async selectSpeakers(roundNumber: number): Promise<Participant[]> {
+    const candidates = this.participantManager.getNonHost()
+    if (!this.coordinator) return candidates
+
+    try {
+      const selected = await this.coordinator({
+        topic: this.topic,
+        round: roundNumber,
+        latestMessage: this.roundManager.getLatestMessages(1)[0] ?? null,
+        candidates,
+        ask: async (participant, prompt) => {
+          const response = await this.callModel(participant, prompt, {
+            timeout: this.responseTimeoutFor(participant),
+          })
+          return response.content
+        },
+      })
+      const candidateIds = new Set(candidates.map(p => p.id))
+      return selected.filter(p => candidateIds.has(p.id))
+    } catch (error) {
+      logger.warn('coordinator failed', { error: error instanceof Error ? error.message : String(error) })
+      return candidates
+    }
+  }
+
+  /**
+   * The participant's latest reply in the current round, or '' when none
+   */
+  private latestReplyFrom(participant: Participant): string {
+    return this.roundManager
+      .getMessagesFrom(participant.id)
+      .filter(m => m.type === 'assistant' && m.round === this.roundManager.currentRoundNumber)
+      .pop()?.content ?? ''
+  }
+
+  /**
+   * The latest reply in the current round from someone else that
+   * @mentions the participant, which their next reply answers
+   */
+  private mentionToAnswer(participant: Participant): Message | undefined {
+    return this.roundManager
+      .getCurrentRoundMessages()
+      .filter(m => m.type === 'assistant' && m.metadata?.participantId !== participant.id)
+      .reverse()
+      .find(m => this.mentions(m.content, participant))
+  }
+
+  /**
+   * Whether a message @mentions a participant, resolving overlapping
+   * names against everyone in the council
+   */
+  private mentions(text: string, participant: Participant): boolean {
+    return isMentioned(text, participant, this.participantManager.getAll())
+  }
+
+  /**
+   * In mention mode, keep only participants the host's reply @mentions
+   * (or, in the first round, the topic does)
+   */
+  private filterByMention(speakers: Participant[], host: Participant, roundNumber: number): Participant[] {
+    if (!this.config.mentionMode) return speakers
+
+    const hostReply = this.latestReplyFrom(host)
+    return speakers.filter(p =>
+      this.mentions(hostReply, p) || (roundNumber === 1 && this.mentions(this.topic, p))
+    )
+  }
+
+  /**
+   * Order speakers so those a message @mentions come first, otherwise
+   * keeping their order
+   */
+  private mentionedFirst(speakers: Participant[], text: string): Participant[] {
+    const mentioned = (p: Participant) => this.mentions(text, p)
+    return [
+      ...speakers.filter(mentioned),
+      ...speakers.filter(p => !mentioned(p)),
+    ]
+  }
+
+  /**
+   * Limit a round's speakers to maxSpeakersPerRound, keeping those the host
+   * mentioned first and noting who sits the round out
+   */
+  private capSpeakers(speakers: Participant[], host: Participant): Participant[] {
+    const limit = this.config.maxSpeakersPerRound
+    if (limit <= 0 || speakers.length <= limit) return speakers
+
+    const ordered = this.mentionedFirst(speakers, this.latestReplyFrom(host))
+    const kept = ordered.slice(0, limit)
+    const skipped = ordered.slice(limit)
+
+    const notice = this.roundManager.addMessage(
+      t('messages.systemMessage'),
+      t('messages.speakersSkipped', { names: skipped.map(p => p.name).join(', ') }),
+      'system',
+      { skippedParticipants: skipped.map(p => p.id) }
+    )
+    if (notice) {
+      this.events.emit('message:new', notice)
+    }
+
+    return kept
+  }
+
+  /**
+   * The discussion so far without system notices, trimmed to the
+   * participant's context budget when it has one
+   */
+  private historyFor(participant: Participant): string {
+    const { maxContextMessages, maxContextTokens } = participant.provider
+    return this.roundManager.getPreviousContext(maxContextMessages ?? Number.MAX_SAFE_INTEGER, {
+      includeSystem: false,
+      maxTokens: maxContextTokens,
+      omittedNote: t('messages.earlierOmitted'),
+    })
+  }
+
+  /**
+   * Build the primer for a participant joining mid-discussion
+   */
+  private lateJoinPrimer(participant: Participant): string {
+    const params = {
+      topic: this.topic,
+      participants: this.participantManager.getParticipantNames(),
+      history: this.historyFor(participant),
+    }
+    return this.config.lateJoinPrimer
+      ? interpolate(this.config.lateJoinPrimer, params)
+      : t('prompts.lateJoinPrimer', params)
+  }
+
+  /**
+   * Post each pending participant introduction, host first
+   */
+  private async runIntroductions(): Promise<void> {
+    const pending = [
+      ...this.participantManager.getAll().filter(p => p.isHost),
+      ...this.participantManager.getNonHost(),
+    ].filter(p => p.firstTurnPrompt && !this.introduced.has(p.id))
+
+    for (const participant of pending) {
+      this.introduced.add(participant.id)
+      const prompt = t('prompts.introPrompt', {
+        topic: this.topic,
+        instruction: participant.firstTurnPrompt!,
+      })
+      await this.getParticipantResponse(participant, prompt, participant.isHost, { intro: true })
+    }
+  }
+
   /**
    * Get response from a participant
+   *
+   * @returns false if the provider call failed
    */
   private async getParticipantResponse(
     participant: Participant,
     prompt: string,
-    isHost: boolean
-  ): Promise<void> {
+    isHost: boolean,
+    metadata: Record<string, unknown> = {}
+  ): Promise<boolean> {
     // Update status to thinking
     this.participantManager.updateStatus(participant.id, 'thinking')
     this.events.emit('participant:thinking', participant)
     this.emitStateChange()
 
     try {
-      // Build system prompt
-      const systemPrompt = isHost
-        ? t('prompts.hostSystemPrompt', {
-            participants: this.participantManager.getParticipantNames(true),
-            topic: this.topic,
-          })
-        : t('prompts.participantSystemPrompt', {
-            host: this.participantManager.getHost()?.name ?? '',
-            participants: this.participantManager.getParticipantNames(true),
-            topic: this.topic,
-          })
+      const systemPrompt = this.systemPromptFor(participant, isHost)
+      const answering = metadata.intro ? undefined : this.mentionToAnswer(participant)
 
-      // Call the model
-      const response = await providerAdapter.call(participant, prompt, {
+      // Call the model, streaming deltas only when someone is listening.
+      // Every request (retries, elaborations, continuations) streams anew.
+      const callOptions = {
         systemPrompt,
-        timeout: this.config.responseTimeout,
-      })
+        timeout: this.responseTimeoutFor(participant),
+        ...(this.events.listenerCount('participant:delta') > 0 && {
+          onChunk: (text: string) => this.events.emit('participant:delta', participant, text),
+          onAttempt: () => this.events.emit('participant:delta-reset', participant),
+        }),
+      }
+      // Only a full round reply is held to the response schema; introductions
+      // and continuations of a cut-off reply can't match it on their own
+      const replyOptions = { ...callOptions, enforceSchema: !metadata.intro }
+      const startedAt = Date.now()
+      let response = await this.callModel(participant, prompt, replyOptions)
+
+      // Retry once with a nudge when the reply is too short to be substantive
+      if (response.content.trim().length < this.config.minResponseLength) {
+        response = await this.callModel(
+          participant,
+          `${prompt}\n\n${t('prompts.elaboratePrompt')}`,
+          replyOptions
+        )
+      }
+
+      // Stitch on continuations while the reply was cut off at the token limit,
+      // keeping every part's thinking and, once stitched, each raw body in order
+      const rawParts = response.raw !== undefined ? [response.raw] : []
+      for (let i = 0; i < this.config.maxContinuations && isTruncated(response.finishReason); i++) {
+        const next = await this.callModel(
+          participant,
+          t('prompts.continuePrompt', { prompt, partial: response.content }),
+          callOptions
+        )
+        if (next.raw !== undefined) rawParts.push(next.raw)
+        const thinking = [response.thinking, next.thinking].filter(Boolean).join('\n\n')
+        response = {
+          ...next,
+          content: response.content + next.content,
+          usage: {
+            inputTokens: (response.usage?.inputTokens ?? 0) + (next.usage?.inputTokens ?? 0),
+            outputTokens: (response.usage?.outputTokens ?? 0) + (next.usage?.outputTokens ?? 0),
+          },
+          thinking: thinking || undefined,
+          raw: rawParts.length > 0 ? rawParts : undefined,
+        }
+      }
+
+      // A participant removed while its call was in flight doesn't get to post
+      if (!this.participantManager.get(participant.id)) {
+        logger.debug('reply dropped', { participant: participant.name, reason: 'removed' })
+        return true
+      }
 
       // Update status
       this.participantManager.updateStatus(participant.id, 'idle')
 
-      // Add message to round
+      // Suppress verbatim repeats of the participant's own recent replies
+      if (this.isDuplicateReply(participant, response.content)) {
+        const notice = this.roundManager.addMessage(
+          participant.name,
+          t('messages.duplicateSuppressed', { name: participant.name }),
+          'system',
+          { participantId: participant.id, duplicate: true }
+        )
+        if (notice) {
+          this.events.emit('message:new', notice)
+        }
+        this.emitStateChange()
+        return true
+      }
+
+      // Add message to round, recording where and how the reply came
+      const replyMetadata: ReplyMetadata = {
+        participantId: participant.id,
+        isHost,
+        provider: participant.provider.id,
+        model: participant.provider.modelId,
+        latencyMs: Date.now() - startedAt,
+        ...(response.thinking && { thinking: response.thinking }),
+        ...(response.usage && { usage: response.usage }),
+        ...(answering && { replyTo: answering.id }),
+        ...(this.config.keepRawResponses && response.raw !== undefined && {
+          rawResponse: JSON.parse(
+            redactSecrets(JSON.stringify(response.raw), [participant.provider.apiKey])
+          ),
+        }),
+      }
       const message = this.roundManager.addMessage(
         participant.name,
         response.content,
         'assistant',
-        { participantId: participant.id, isHost }
+        { ...metadata, ...replyMetadata }
       )
 
       if (message) {
+        logger.debug('reply added', { participant: participant.name, messageId: message.id, round: message.round })
         this.events.emit('message:new', message)
       }
 
       this.events.emit('participant:response', participant, response.content)
     } catch (error) {
-      this.participantManager.updateStatus(participant.id, 'error')
       const err = error instanceof Error ? error : new Error(String(error))
+      if (!this.participantManager.get(participant.id)) {
+        logger.debug('failure dropped', { participant: participant.name, reason: 'removed', error: err.message })
+        return true
+      }
+
+      this.participantManager.updateStatus(participant.id, 'error')
+      logger.warn('participant failed', { participant: participant.name, error: err.message })
       this.events.emit('participant:error', participant, err)
 
-      // Add error message
+      // Add error message; a rejected key won't fix itself, so say so
+      const kind = err instanceof ProviderError ? err.kind : 'unknown'
       this.roundManager.addMessage(
         participant.name,
-        t('errors.providerError', { message: err.message }),
+        kind === 'auth'
+          ? t('errors.providerAuthError', { name: participant.name, message: err.message })
+          : t('errors.providerError', { message: err.message }),
         'system',
-        { participantId: participant.id, error: true }
+        { participantId: participant.id, error: true, errorKind: kind }
       )
+      this.emitStateChange()
+      return false
     }
 
     this.emitStateChange()
+    return true
+  }
+
+  /**
+   * Call a participant's model, counting the tokens it reports
+   */
+  private async callModel(
+    participant: Participant,
+    prompt: string,
+    options: ModelCallOptions
+  ): Promise<ModelResponse> {
+    const response = await this.adapter.call(participant, prompt, options)
+    if (!response.usage) this.usageUnavailable = true
+    const totals = this.usage.get(participant.id) ?? { inputTokens: 0, outputTokens: 0, calls: 0 }
+    totals.inputTokens += response.usage?.inputTokens ?? 0
+    totals.outputTokens += response.usage?.outputTokens ?? 0
+    totals.calls++
+    this.usage.set(participant.id, totals)
+    return response
+  }
+
+  /**
+   * Send a participant a one-off request outside the discussion, such as
+   * a connection test, through the council's adapter; it isn't counted in usage
+   */
+  async probe(participant: Participant, prompt: string, options: ModelCallOptions = {}): Promise<ModelResponse> {
+    return this.adapter.call(participant, prompt, options)
+  }
+
+  /**
+   * Token usage per participant and in total, with an estimated cost
+   * where the models have pricing configured
+   *
+   * Participants who have since been removed are still reported, since
+   * their calls were made and paid for.
+   *
+   * @returns undefined when some call reported no token counts, as OpenCode
+   *   client replies may not, rather than totals that undercount
+   */
+  getUsage(): UsageReport | undefined {
+    if (this.usageUnavailable) return undefined
+
+    const participants = [...this.usage].flatMap(([id, totals]) => {
+      const p = this.participantManager.get(id) ?? this.removed.get(id)?.participant
+      if (!p) return []
+
+      const { pricing } = p.provider
+      return [{
+        participantId: p.id,
+        name: p.name,
+        modelId: p.provider.modelId,
+        ...totals,
+        ...(pricing && {
+          estimatedCost: (totals.inputTokens * pricing.input + totals.outputTokens * pricing.output) / 1_000_000,
+        }),
+      }]
+    })
+
+    const total: UsageTotals = {
+      inputTokens: participants.reduce((sum, p) => sum + p.inputTokens, 0),
+      outputTokens: participants.reduce((sum, p) => sum + p.outputTokens, 0),
+      calls: participants.reduce((sum, p) => sum + p.calls, 0),
+    }
+    if (participants.length > 0 && participants.every(p => p.estimatedCost !== undefined)) {
+      total.estimatedCost = participants.reduce((sum, p) => sum + p.estimatedCost!, 0)
+    }
+
+    return { participants, total }
+  }
+
+  /**
+   * Resolve a participant's response timeout: its own override, otherwise
+   * the council default scaled by model family
+   */
+  private responseTimeoutFor(participant: Participant): number {
+    const { provider } = participant
+    if (provider.timeout !== undefined) return provider.timeout
+
+    const scale = MODEL_TIMEOUT_SCALES.find(([pattern]) => pattern.test(provider.modelId))?.[1] ?? 1
+    return this.config.responseTimeout * scale
+  }
+
+  /**
+   * Check a reply against the participant's recent reply hashes and record it
+   */
+  private isDuplicateReply(participant: Participant, content: string): boolean {
+    const windowSize = this.config.duplicateReplyWindow
+    if (windowSize <= 0) return false
+
+    const hash = hashString(content.trim())
+    const recent = this.replyHashes.get(participant.id) ?? []
+    if (recent.includes(hash)) {
+      return true
+    }
+
+    this.replyHashes.set(participant.id, [...recent, hash].slice(-windowSize))
+    return false
   }
 
   /**
@@ -270,6 +861,116 @@ export class Council {
     return this.runRound()
   }
 
+  /**
+   * Run a slash command sent into the discussion, e.g. `/kick Claude`
+   *
+   * Commands act on the discussion instead of starting a round. Unknown
+   * commands and bad arguments are posted to the discussion as system
+   * messages.
+   */
+  async runCommand(text: string): Promise<CommandResult> {
+    if (this.status !== 'running' && this.status !== 'paused') {
+      throw new Error(t('errors.noActiveDiscussion'))
+    }
+
+    const command = parseCommand(text)
+    const fail = (message: string): CommandResult => {
+      this.postNotice(message, { command: command?.name ?? text, error: true })
+      return { ok: false, message }
+    }
+    const usage = (name: string) => fail(t('discussion.commandUsage', { usage: COMMAND_USAGE[name]! }))
+
+    switch (command?.name) {
+      case 'done':
+        await this.endDiscussion()
+        return { ok: true, message: t('discussion.completed') }
+
+      case 'summary': {
+        let summary: Message | null
+        try {
+          summary = await this.summarize()
+        } catch (error) {
+          return fail(error instanceof Error ? error.message : String(error))
+        }
+        return summary
+          ? { ok: true, message: summary.content }
+          : { ok: false, message: t('messages.noMessages') }
+      }
+
+      case 'kick': {
+        const key = command.args.join(' ')
+        if (!key) return usage('kick')
+        const participant = this.findParticipant(key)
+        if (!participant) return fail(t('errors.modelNotFound', { model: key }))
+        if (participant.isHost) return fail(t('errors.cannotRemoveHost', { name: participant.name }))
+        this.removeParticipant(participant.id)
+        return { ok: true, message: t('participant.left', { name: participant.name }) }
+      }
+
+      case 'add': {
+        const key = command.args.join(' ')
+        if (!key) return usage('add')
+        const removed = [...this.removed.values()]
+        const entry = removed.find(({ participant: p }) => p.id === key) ??
+          removed.find(({ participant: p }) => p.name.toLowerCase() === key.toLowerCase())
+        if (!entry) return fail(t('errors.removedParticipantNotFound', { name: key }))
+        const { participant: former, options } = entry
+        if (this.findParticipant(former.name)) return fail(t('errors.duplicateParticipant', { name: former.name }))
+        this.removed.delete(former.id)
+        // A former host only takes the role back if no one holds it now
+        const participant = this.addParticipant(former.provider, {
+          ...options,
+          isHost: options.isHost && !this.participantManager.hasHost,
+        })
+        // Someone who already introduced themselves doesn't do it again
+        if (this.introduced.has(former.id)) {
+          this.introduced.add(participant.id)
+        }
+        // Carry the earlier usage over so it stays in the report
+        const totals = this.usage.get(former.id)
+        if (totals) {
+          this.usage.delete(former.id)
+          this.usage.set(participant.id, totals)
+        }
+        return { ok: true, message: t('participant.joined', { name: participant.name }) }
+      }
+
+      case 'rounds': {
+        const rounds = Number(command.args[0])
+        if (!Number.isInteger(rounds) || rounds < 0) return usage('rounds')
+        this.config.maxRounds = rounds
+        const message = t('discussion.roundLimitChanged', { rounds })
+        this.postNotice(message, { command: 'rounds', roundLimit: rounds })
+        this.emitStateChange()
+        return { ok: true, message }
+      }
+
+      default:
+        return fail(t('discussion.commandUnknown', {
+          command: text.trim().split(/\s+/)[0]!,
+          commands: Object.values(COMMAND_USAGE).join(', '),
+        }))
+    }
+  }
+
+  /**
+   * Find a participant by ID, or by name case-insensitively
+   */
+  findParticipant(key: string): Participant | undefined {
+    const all = this.participantManager.getAll()
+    return all.find(p => p.id === key) ?? all.find(p => p.name.toLowerCase() === key.toLowerCase())
+  }
+
+  /**
+   * Post a system message to the current round
+   */
+  private postNotice(content: string, metadata: Record<string, unknown>): void {
+    const notice = this.roundManager.addMessage(t('messages.systemMessage'), content, 'system', metadata)
+    if (notice) {
+      this.events.emit('message:new', notice)
+    }
+  }
+
   /**
    * End the discussion
    */
@@ -278,6 +979,23 @@ export class Council {
       return
     }
 
+    // Summarize while the discussion is still open
+    if (this.config.summarizeOnEnd && !this.finalSummary) {
+      try {
+        await this.summarize()
+      } catch (error) {
+        const notice = this.roundManager.addMessage(
+          t('messages.systemMessage'),
+          t('errors.summaryFailed', { message: error instanceof Error ? error.message : String(error) }),
+          'system',
+          { error: true }
+        )
+        if (notice) {
+          this.events.emit('message:new', notice)
+        }
+      }
+    }
+
     this.status = 'completed'
     this.endedAt = new Date()
 
@@ -290,6 +1008,69 @@ export class Council {
     this.emitStateChange()
   }
 
+  /**
+   * Ask for a final consensus of the whole discussion
+   *
+   * The summarizer (the configured participant, otherwise the host) gets
+   * the full transcript and is asked for the agreed answer and the points
+   * still in dispute. The reply is added as a `summary` message.
+   *
+   * @returns The summary message, or null if nothing has been said yet
+   */
+  async summarize(): Promise<Message | null> {
+    if (!this.roundManager.getAllMessages().some(m => m.type === 'assistant')) {
+      return null
+    }
+
+    const summarizer = this.getSummarizer()
+    if (!summarizer) {
+      throw new Error(t('errors.modelNotFound', { model: this.config.summarizer }))
+    }
+
+    const response = await this.callModel(
+      summarizer,
+      t('prompts.finalSummaryPrompt', {
+        topic: this.topic,
+        history: this.historyFor(summarizer),
+      }),
+      { timeout: this.responseTimeoutFor(summarizer) }
+    )
+
+    const message = this.roundManager.addMessage(
+      summarizer.name,
+      response.content,
+      'summary',
+      { participantId: summarizer.id }
+    )
+    if (message) {
+      this.finalSummary = message
+      this.events.emit('message:new', message)
+      this.events.emit('summary:generated', message)
+      this.emitStateChange()
+    }
+    return message
+  }
+
+  /**
+   * Resolve the participant that writes the final summary
+   */
+  private getSummarizer(): Participant | undefined {
+    const key = this.config.summarizer.toLowerCase()
+    if (!key) {
+      return this.participantManager.getHost()
+    }
+    return this.participantManager.getAll().find(p =>
+      p.name.toLowerCase() === key || p.provider.id.toLowerCase() === key
+    )
+  }
+
+  /**
+   * Look up a message from any round by ID
+   */
+  getMessage(id: string): Message | null {
+    return this.roundManager.getMessage(id)
+  }
+
   /**
    * Pause the discussion
    */
@@ -319,6 +1100,13 @@ export class Council {
     this.status = 'idle'
     this.startedAt = null
     this.endedAt = null
+    this.replyHashes.clear()
+    this.introduced.clear()
+    this.lateJoiners.clear()
+    this.removed.clear()
+    this.finalSummary = null
+    this.usage.clear()
+    this.usageUnavailable = false
     this.participantManager.clear()
     this.roundManager.clear()
     this.emitStateChange()
@@ -350,6 +1138,9 @@ export class Council {
     if (callbacks.onResponse) {
       this.on('participant:response', callbacks.onResponse)
     }
+    if (callbacks.onDelta) {
+      this.on('participant:delta', callbacks.onDelta)
+    }
     if (callbacks.onError) {
       this.on('participant:error', (participant, error) => {
         callbacks.onError!(error, participant)
@@ -403,6 +1194,14 @@ export class Council {
   get isComplete(): boolean {
     return this.status === 'completed'
   }
+
+  get summary(): Message | null {
+    return this.finalSummary
+  }
+
+  get showThinking(): boolean {
+    return this.config.showThinking
+  }
 }
 
 // Singleton instance for the plugin
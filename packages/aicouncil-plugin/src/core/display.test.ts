@@ -0,0 +1,129 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
+import { attachStreamingDisplay, type LineRenderer } from './display'
+import { getCouncil, resetCouncil } from './council'
+import { providerAdapter } from '../providers/adapter'
+import type { ProviderConfig } from '../types'
+
+vi.mock('../providers/adapter', () => ({
+  providerAdapter: {
+    setClient: vi.fn(),
+    call: vi.fn(),
+  },
+  ProviderAdapter: vi.fn(),
+}))
+
+const host: ProviderConfig = {
+  id: 'host',
+  name: 'Host',
+  baseURL: 'https://api.test1.com',
+  apiKey: 'key-1',
+  modelId: 'model-1',
+}
+
+const member: ProviderConfig = {
+  id: 'member',
+  name: 'Member',
+  baseURL: 'https://api.test2.com',
+  apiKey: 'key-2',
+  modelId: 'model-2',
+}
+
+function captureRenderer(live: boolean) {
+  const lines: string[] = []
+  let current: string | null = null
+  const updates: string[] = []
+  const renderer: LineRenderer = {
+    live,
+    update(text) {
+      current = text
+      updates.push(text)
+    },
+    commit(text) {
+      current = null
+      lines.push(text)
+    },
+  }
+  return { renderer, lines, updates, current: () => current }
+}
+
+describe('attachStreamingDisplay', () => {
+  beforeEach(() => {
+    vi.clearAllMocks()
+    resetCouncil()
+  })
+
+  afterEach(() => {
+    resetCouncil()
+  })
+
+  it('should redraw one live line per reply and finalize it', async () => {
+    const council = getCouncil({ maxRounds: 1 })
+    council.addParticipant(host, { isHost: true })
+    council.addParticipant(member)
+    vi.mocked(providerAdapter.call).mockImplementation(async (participant, _prompt, options) => {
+      options?.onChunk?.('Hello')
+      options?.onChunk?.(' there')
+      return { content: `Hello there from ${participant.name}` }
+    })
+
+    const capture = captureRenderer(true)
+    const detach = attachStreamingDisplay(council, capture.renderer)
+    await council.startDiscussion('Greetings')
+    detach()
+
+    expect(capture.updates).toEqual([
+      '[Host]: Hello',
+      '[Host]: Hello there',
+      '[Member]: Hello',
+      '[Member]: Hello there',
+    ])
+    expect(capture.lines).toEqual([
+      '[Host]: Hello there from Host',
+      '[Member]: Hello there from Member',
+    ])
+    expect(capture.current()).toBeNull()
+  })
+
+  it('should drop the deltas of a request that is retried', async () => {
+    const council = getCouncil({ maxRounds: 1, minResponseLength: 20 })
+    council.addParticipant(host, { isHost: true })
+    council.addParticipant(member)
+    vi.mocked(providerAdapter.call)
+      .mockImplementationOnce(async (_participant, _prompt, options) => {
+        options?.onAttempt?.()
+        options?.onChunk?.('Hi')
+        return { content: 'Hi' }
+      })
+      .mockImplementation(async (participant, _prompt, options) => {
+        options?.onAttempt?.()
+        options?.onChunk?.('A longer greeting')
+        return { content: `A longer greeting from ${participant.name}` }
+      })
+
+    const capture = captureRenderer(true)
+    attachStreamingDisplay(council, capture.renderer)
+    await council.startDiscussion('Greetings')
+
+    expect(capture.updates).toEqual([
+      '[Host]: Hi',
+      '[Host]: A longer greeting',
+      '[Member]: A longer greeting',
+    ])
+    expect(capture.lines[0]).toBe('[Host]: A longer greeting from Host')
+  })
+
+  it('should only render complete messages when the output is not live', async () => {
+    const council = getCouncil({ maxRounds: 1 })
+    council.addParticipant(host, { isHost: true })
+    council.addParticipant(member)
+    vi.mocked(providerAdapter.call).mockResolvedValue({ content: 'Done' })
+
+    const capture = captureRenderer(false)
+    attachStreamingDisplay(council, capture.renderer)
+    await council.startDiscussion('Greetings')
+
+    expect(vi.mocked(providerAdapter.call).mock.calls[0][2]?.onChunk).toBeUndefined()
+    expect(capture.updates).toEqual([])
+    expect(capture.lines).toEqual(['[Host]: Done', '[Member]: Done'])
+  })
+})
@@ -0,0 +1,75 @@
+/**
+ * Streaming Display
+ *
+ * Renders participant replies as they stream in, one live line at a time.
+ * It writes straight to the terminal, so it is only attached when the
+ * `liveDisplay` option is set; inside the OpenCode TUI it would garble the
+ * host's own rendering.
+ */
+
+import type { Council } from './council'
+import type { Message } from '../types'
+
+/**
+ * Output target for the streaming display
+ */
+export interface LineRenderer {
+  /** Whether the output can redraw a line in place (a TTY) */
+  live: boolean
+  /** Redraw the current live line */
+  update(text: string): void
+  /** Finish the current line with its final text */
+  commit(text: string): void
+}
+
+/**
+ * Create a renderer over a terminal stream, stderr by default so stdout
+ * stays free for the caller's own output
+ *
+ * Non-TTY streams are not live, so the display falls back to printing
+ * complete messages only.
+ */
+export function createTerminalRenderer(stream: NodeJS.WriteStream = process.stderr): LineRenderer {
+  return {
+    live: stream.isTTY === true,
+    update(text) {
+      stream.write(`\r\x1b[2K${text}`)
+    },
+    commit(text) {
+      stream.write(stream.isTTY ? `\r\x1b[2K${text}\n` : `${text}\n`)
+    },
+  }
+}
+
+/**
+ * Show participants' replies as they stream, finalizing each on completion
+ *
+ * Deltas are only requested from the council when the renderer is live;
+ * otherwise, or when a provider doesn't stream, each reply is rendered once
+ * it is complete.
+ *
+ * @returns Function that detaches the display
+ */
+export function attachStreamingDisplay(council: Council, renderer: LineRenderer): () => void {
+  const partials = new Map<string, string>()
+  const unsubscribers: Array<() => void> = []
+
+  if (renderer.live) {
+    // Each request streams from scratch, so drop what an earlier one sent
+    unsubscribers.push(council.on('participant:delta-reset', participant => {
+      partials.delete(participant.name)
+    }))
+    unsubscribers.push(council.on('participant:delta', (participant, delta) => {
+      const partial = (partials.get(participant.name) ?? '') + delta
+      partials.set(participant.name, partial)
+      renderer.update(`[${participant.name}]: ${partial}`)
+    }))
+  }
+
+  unsubscribers.push(council.on('message:new', (message: Message) => {
+    partials.delete(message.from)
+    renderer.commit(`[${message.from}]: ${message.content}`)
+  }))
+
+  return () => unsubscribers.forEach(unsubscribe => unsubscribe())
+}
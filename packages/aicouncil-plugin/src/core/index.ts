@@ -8,3 +8,9 @@ export { getCouncil, resetCouncil } from './council'
 export type { Council } from './council'
 export { ParticipantManager, createParticipant, updateParticipantStatus } from './participant'
 export { RoundManager, createRound, createMessage } from './round'
+export { createModerator, parseModeratorReply } from './moderator'
+export { findMentions, isMentioned } from './mentions'
+export { isCommand, parseCommand, COMMAND_USAGE } from './commands'
+export type { ParsedCommand, CommandResult } from './commands'
+export { attachStreamingDisplay, createTerminalRenderer } from './display'
+export type { LineRenderer } from './display'
@@ -0,0 +1,75 @@
+import { describe, it, expect } from 'vitest'
+import { findMentions, isMentioned } from './mentions'
+import type { Participant } from '../types'
+
+function participant(id: string, name: string): Participant {
+  return {
+    id,
+    name,
+    provider: { id, name, baseURL: '', apiKey: 'key', modelId: 'model' },
+    isHost: false,
+    status: 'idle',
+  }
+}
+
+const gpt = participant('p1', 'gpt-4o')
+const mini = participant('p2', 'gpt-4o-mini')
+const claude = participant('p3', 'Claude')
+const everyone = [gpt, mini, claude]
+
+describe('findMentions', () => {
+  it('should not let a name match a longer name it prefixes', () => {
+    expect(findMentions('@gpt-4o-mini, your turn', everyone)).toEqual([mini])
+    expect(findMentions('@gpt-4o, your turn', everyone)).toEqual([gpt])
+    expect(findMentions('@gpt-4o and @gpt-4o-mini', everyone)).toEqual([gpt, mini])
+  })
+
+  it('should match case-insensitively next to punctuation', () => {
+    expect(findMentions('(@claude) what do you think?', everyone)).toEqual([claude])
+    expect(findMentions('Over to you, @CLAUDE.', everyone)).toEqual([claude])
+  })
+
+  it('should ignore mentions inside words and email addresses', () => {
+    expect(findMentions('Write to team@Claude.example', everyone)).toEqual([])
+    expect(findMentions('@Claudette has a point', everyone)).toEqual([])
+  })
+
+  it('should ignore mentions inside code', () => {
+    const reply = 'Try `@Claude` in config:\n```\nowner: @gpt-4o\n```\nThoughts?'
+    expect(findMentions(reply, everyone)).toEqual([])
+  })
+
+  it('should credit the longer of two names matching at the same @', () => {
+    const alice = participant('a', 'Alice')
+    const aliceB = participant('b', 'Alice B')
+    expect(findMentions('@Alice B, go ahead', [alice, aliceB])).toEqual([aliceB])
+    expect(findMentions('@Alice, go ahead', [alice, aliceB])).toEqual([alice])
+  })
+})
+
+describe('findMentions by ID and normalized name', () => {
+  it('should match a participant by its ID', () => {
+    expect(findMentions('@p3 please review', everyone)).toEqual([claude])
+  })
+
+  it('should collapse spaces in names', () => {
+    const opus = participant('o1', 'Claude Opus')
+    expect(findMentions('@claude   opus, thoughts?', [opus])).toEqual([opus])
+    expect(findMentions('@Claude\nOpus, thoughts?', [opus])).toEqual([opus])
+  })
+
+  it('should prefer an exact ID match over a name', () => {
+    const byName = participant('x1', 'reviewer')
+    const byId = participant('reviewer', 'Second Opinion')
+    expect(findMentions('@reviewer go ahead', [byName, byId])).toEqual([byId])
+    expect(findMentions('@Reviewer go ahead', [byName, byId])).toEqual([byName])
+  })
+})
+
+describe('isMentioned', () => {
+  it('should check one participant against the rest', () => {
+    expect(isMentioned('@gpt-4o-mini go', gpt, everyone)).toBe(false)
+    expect(isMentioned('@gpt-4o-mini go', mini, everyone)).toBe(true)
+    expect(isMentioned('@Claude go', claude)).toBe(true)
+  })
+})
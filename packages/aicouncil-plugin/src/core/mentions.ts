@@ -0,0 +1,76 @@
+/**
+ * Mentions
+ *
+ * Finds which participants a message @mentions
+ */
+
+import type { Participant } from '../types'
+
+/**
+ * Blank out fenced and inline code, where an @ is rarely meant as a mention
+ */
+function stripCode(text: string): string {
+  return text
+    .replace(/```[\s\S]*?(?:```|$)/g, ' ')
+    .replace(/`[^`\n]*`/g, ' ')
+}
+
+function escapeRegExp(text: string): string {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&')
+}
+
+/**
+ * Build a mention pattern for a handle; any run of whitespace in the
+ * handle matches any run of whitespace in the text
+ */
+function mentionPattern(handle: string, flags: string): RegExp {
+  const body = handle.trim().split(/\s+/).map(escapeRegExp).join('\\s+')
+  return new RegExp(`(?<![\\w@])@${body}(?![\\w-])`, flags)
+}
+
+/**
+ * Find the participants a message @mentions, in the order given
+ *
+ * A mention is `@` plus the participant's ID, exactly, or its display
+ * name, case-insensitively and with spaces collapsed (`@claude  opus`
+ * mentions "Claude Opus"). It must not be preceded by a word character
+ * (so email addresses don't count) or followed by a word character or
+ * hyphen (so `@gpt-4o` doesn't mention `gpt-4o-mini`). Mentions inside
+ * code are ignored.
+ *
+ * When several participants match at the same `@`, one exact ID match
+ * wins over any name, then the longest name wins, then the participant
+ * listed first.
+ */
+export function findMentions(text: string, participants: Participant[]): Participant[] {
+  const source = stripCode(text)
+
+  const claimed = new Map<number, { participant: Participant; rank: number }>()
+  const claim = (pattern: RegExp, participant: Participant, rank: (match: string) => number) => {
+    for (const match of source.matchAll(pattern)) {
+      const current = claimed.get(match.index!)
+      const matchRank = rank(match[0])
+      if (!current || matchRank > current.rank) {
+        claimed.set(match.index!, { participant, rank: matchRank })
+      }
+    }
+  }
+
+  for (const participant of participants) {
+    claim(mentionPattern(participant.id, 'g'), participant, () => Number.POSITIVE_INFINITY)
+    claim(mentionPattern(participant.name, 'gi'), participant, match => match.length)
+  }
+
+  const mentioned = new Set([...claimed.values()].map(c => c.participant.id))
+  return participants.filter(p => mentioned.has(p.id))
+}
+
+/**
+ * Whether a message @mentions a participant
+ *
+ * @param participants - Everyone who could be mentioned, so overlapping
+ *   mentions resolve as in findMentions; defaults to just `participant`
+ */
+export function isMentioned(text: string, participant: Participant, participants: Participant[] = [participant]): boolean {
+  return findMentions(text, participants).some(p => p.id === participant.id)
+}
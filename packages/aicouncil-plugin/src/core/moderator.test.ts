@@ -0,0 +1,62 @@
+import { describe, it, expect, vi } from 'vitest'
+import { createModerator, parseModeratorReply } from './moderator'
+import type { Participant } from '../types'
+
+function participant(id: string, name: string, isHost = false): Participant {
+  return {
+    id,
+    name,
+    provider: { id, name, baseURL: '', apiKey: 'key', modelId: 'model' },
+    isHost,
+    status: 'idle',
+  }
+}
+
+const host = participant('host', 'Host', true)
+const alice = participant('p1', 'Alice')
+const bob = participant('p2', 'Bob')
+const carol = participant('p3', 'Carol')
+const candidates = [alice, bob, carol]
+
+describe('parseModeratorReply', () => {
+  it('should return the picked participants in the moderator\'s order', () => {
+    expect(parseModeratorReply('Sure: ["Carol", "alice"]', candidates)).toEqual([carol, alice])
+  })
+
+  it('should use the last list when the reply restates the candidates first', () => {
+    const reply = 'The candidates are ["Alice", "Bob", "Carol"]. My pick: ["Bob"]'
+    expect(parseModeratorReply(reply, candidates)).toEqual([bob])
+  })
+
+  it('should accept IDs and ignore unknown or repeated names', () => {
+    expect(parseModeratorReply('["p2", "Dave", "Bob"]', candidates)).toEqual([bob])
+  })
+
+  it('should throw when no list can be parsed', () => {
+    expect(() => parseModeratorReply('Everyone should speak.', candidates)).toThrow('no JSON list')
+    expect(() => parseModeratorReply('["Dave"]', candidates)).toThrow('no known participant')
+  })
+})
+
+describe('createModerator', () => {
+  it('should ask the moderator model and return its picks', async () => {
+    const ask = vi.fn().mockResolvedValue('["Bob", "Alice", "Carol"]')
+    const moderate = createModerator(host, 2)
+
+    const selected = await moderate({ topic: 'Caching', round: 2, latestMessage: null, candidates, ask })
+
+    expect(selected).toEqual([bob, alice])
+    const [called, prompt] = ask.mock.calls[0]
+    expect(called).toBe(host)
+    expect(prompt).toContain('Participants: Alice, Bob, Carol')
+    expect(prompt).toContain('up to 2 participants')
+  })
+
+  it('should throw on an unparseable reply so the council falls back to everyone', async () => {
+    const ask = vi.fn().mockResolvedValue('I cannot decide.')
+
+    await expect(
+      createModerator(host)({ topic: 'Caching', round: 1, latestMessage: null, candidates, ask })
+    ).rejects.toThrow()
+  })
+})
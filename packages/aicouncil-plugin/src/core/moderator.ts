@@ -0,0 +1,59 @@
+/**
+ * Moderator
+ *
+ * A coordinator that asks a model which participants should speak
+ */
+
+import type { Coordinator, Participant } from '../types'
+import { t } from '../i18n'
+
+/**
+ * Match the names or IDs a moderator picked to candidates, in its order
+ *
+ * The last JSON list in the reply is used, since models often restate the
+ * candidates or think aloud before giving their answer.
+ */
+export function parseModeratorReply(reply: string, candidates: Participant[]): Participant[] {
+  const list = reply.match(/\[[^[\]]*\]/g)?.pop()
+  if (!list) {
+    throw new Error('Moderator reply has no JSON list')
+  }
+
+  const picks = JSON.parse(list) as unknown[]
+  const selected: Participant[] = []
+  for (const pick of picks) {
+    if (typeof pick !== 'string') continue
+    const key = pick.trim().toLowerCase()
+    const match = candidates.find(p => p.id.toLowerCase() === key || p.name.toLowerCase() === key)
+    if (match && !selected.includes(match)) {
+      selected.push(match)
+    }
+  }
+
+  if (selected.length === 0) {
+    throw new Error('Moderator reply names no known participant')
+  }
+  return selected
+}
+
+/**
+ * Create a coordinator that lets a model choose who responds each round
+ *
+ * The moderator is asked for a JSON list of participant names in speaking
+ * order. If the call fails or the reply can't be parsed the coordinator
+ * throws, and the council falls back to asking everyone.
+ */
+export function createModerator(moderator: Participant, maxSpeakers?: number): Coordinator {
+  return async ({ topic, round, latestMessage, candidates, ask }) => {
+    const prompt = t('prompts.moderatorPrompt', {
+      topic,
+      round,
+      latest: latestMessage ? `[${latestMessage.from}]: ${latestMessage.content}` : '-',
+      candidates: candidates.map(p => p.name).join(', '),
+      max: maxSpeakers ?? candidates.length,
+    })
+
+    const selected = parseModeratorReply(await ask(moderator, prompt), candidates)
+    return maxSpeakers ? selected.slice(0, maxSpeakers) : selected
+  }
+}
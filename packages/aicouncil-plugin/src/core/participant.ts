@@ -4,7 +4,7 @@
  * Handles participant lifecycle and state management
  */
 
-import type { Participant, ParticipantStatus, ProviderConfig } from '../types'
+import type { Participant, ParticipantOptions, ParticipantStatus, ProviderConfig } from '../types'
 import { generateId } from '../utils'
 
 /**
@@ -12,10 +12,7 @@ import { generateId } from '../utils'
  */
 export function createParticipant(
   provider: ProviderConfig,
-  options: {
-    isHost?: boolean
-    name?: string
-  } = {}
+  options: ParticipantOptions = {}
 ): Participant {
   return {
     id: generateId(),
@@ -23,6 +20,7 @@ export function createParticipant(
     provider,
     isHost: options.isHost ?? false,
     status: 'idle',
+    ...(options.firstTurnPrompt && { firstTurnPrompt: options.firstTurnPrompt }),
   }
 }
 
@@ -69,7 +67,7 @@ export class ParticipantManager {
   /**
    * Add a participant
    */
-  add(provider: ProviderConfig, options?: { isHost?: boolean; name?: string }): Participant {
+  add(provider: ProviderConfig, options?: ParticipantOptions): Participant {
     const participant = createParticipant(provider, options)
     this.participants.set(participant.id, participant)
 
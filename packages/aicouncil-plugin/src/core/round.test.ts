@@ -5,6 +5,7 @@ import {
   RoundManager,
 } from './round'
 import type { MessageType } from '../types'
+import { setIdGenerator } from '../utils'
 
 describe('createRound', () => {
   it('should create a round with given number', () => {
@@ -42,6 +43,18 @@ describe('createMessage', () => {
 
     expect(message.metadata).toEqual(metadata)
   })
+
+  it('should use a pinned ID generator for deterministic IDs', () => {
+    let next = 0
+    setIdGenerator(() => `msg-${++next}`)
+
+    try {
+      expect(createMessage('Alice', 'Hello', 1).id).toBe('msg-1')
+      expect(createMessage('Bob', 'Hi', 1).id).toBe('msg-2')
+    } finally {
+      setIdGenerator(null)
+    }
+  })
 })
 
 describe('RoundManager', () => {
@@ -222,6 +235,46 @@ describe('RoundManager', () => {
     })
   })
 
+  describe('message lookups', () => {
+    it('should find messages by ID, sender and reply target', () => {
+      manager.startNewRound()
+      const question = manager.addMessage('Alice', 'Why?', 'assistant', { participantId: 'p1' })!
+      manager.addMessage('Host', 'Noted')
+      manager.completeCurrentRound()
+
+      manager.startNewRound()
+      const answer = manager.addMessage('Bob', 'Because', 'assistant', { replyTo: question.id })!
+
+      expect(manager.getMessage(question.id)).toBe(question)
+      expect(manager.getMessage('missing')).toBeNull()
+      expect(manager.getMessagesFrom('p1')).toEqual([question])
+      expect(manager.getMessagesFrom('Bob')).toEqual([answer])
+      expect(manager.getReplies(question.id)).toEqual([answer])
+    })
+
+    it('should return the latest messages across rounds, oldest first', () => {
+      manager.startNewRound()
+      manager.addMessage('Alice', 'One')
+      manager.addMessage('Bob', 'Two')
+      manager.completeCurrentRound()
+      manager.startNewRound()
+      manager.addMessage('Alice', 'Three')
+
+      expect(manager.getLatestMessages(2).map(m => m.content)).toEqual(['Two', 'Three'])
+      expect(manager.getLatestMessages(10)).toHaveLength(3)
+      expect(manager.getLatestMessages(0)).toEqual([])
+    })
+
+    it('should forget indexed messages on clear', () => {
+      manager.startNewRound()
+      const message = manager.addMessage('Alice', 'Hello')!
+      manager.clear()
+
+      expect(manager.getMessage(message.id)).toBeNull()
+      expect(manager.getMessagesFrom('Alice')).toEqual([])
+    })
+  })
+
   describe('hasActiveRound', () => {
     it('should return true when round is in progress', () => {
       manager.startNewRound()
@@ -268,6 +321,43 @@ describe('RoundManager', () => {
       const lines = context.split('\n\n')
       expect(lines.length).toBe(5)
     })
+
+    it('should leave out system messages when asked', () => {
+      manager.startNewRound()
+      manager.addMessage('Alice', 'First message')
+      manager.addMessage('System', 'Bob joined the discussion', 'system')
+      manager.addMessage('Bob', 'Second message')
+
+      expect(manager.getPreviousContext()).toContain('Bob joined the discussion')
+      expect(manager.getPreviousContext(10, { includeSystem: false })).toBe(
+        '[Alice]: First message\n\n[Bob]: Second message'
+      )
+    })
+
+    it('should drop the oldest messages to fit a token budget', () => {
+      manager.startNewRound()
+      manager.addMessage('Alice', 'a'.repeat(400))
+      manager.addMessage('Bob', 'b'.repeat(400))
+      manager.addMessage('Carol', 'Short reply')
+
+      const context = manager.getPreviousContext(10, { maxTokens: 120, omittedNote: '[earlier messages omitted]' })
+
+      expect(context).toBe(`[earlier messages omitted]\n\n[Bob]: ${'b'.repeat(400)}\n\n[Carol]: Short reply`)
+    })
+
+    it('should always keep the latest message', () => {
+      manager.startNewRound()
+      manager.addMessage('Alice', 'a'.repeat(400))
+
+      expect(manager.getPreviousContext(10, { maxTokens: 10 })).toBe(`[Alice]: ${'a'.repeat(400)}`)
+    })
+
+    it('should only note omissions when messages were left out', () => {
+      manager.startNewRound()
+      manager.addMessage('Alice', 'First message')
+
+      expect(manager.getPreviousContext(10, { omittedNote: '[earlier messages omitted]' })).toBe('[Alice]: First message')
+    })
   })
 
   describe('clear', () => {
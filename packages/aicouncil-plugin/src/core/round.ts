@@ -5,7 +5,7 @@
  */
 
 import type { Round, RoundStatus, Message, MessageType } from '../types'
-import { generateId } from '../utils'
+import { generateId, estimateTokens } from '../utils'
 
 /**
  * Create a new round
@@ -46,6 +46,9 @@ export function createMessage(
 export class RoundManager {
   private rounds: Round[] = []
   private currentRoundIndex = -1
+  private byId = new Map<string, Message>()
+  private bySender = new Map<string, Message[]>()
+  private byReplyTo = new Map<string, Message[]>()
 
   /**
    * Start a new round
@@ -95,9 +98,64 @@ export class RoundManager {
 
     const message = createMessage(from, content, round.number, type, metadata)
     round.messages.push(message)
+    this.index(message)
     return message
   }
 
+  /**
+   * Index a message by ID, by sender and by the message it replies to.
+   * The sender key is the participant ID when known, else the display name.
+   */
+  private index(message: Message): void {
+    const push = (map: Map<string, Message[]>, key: string) => {
+      const list = map.get(key)
+      if (list) list.push(message)
+      else map.set(key, [message])
+    }
+
+    this.byId.set(message.id, message)
+    const participantId = message.metadata?.participantId
+    push(this.bySender, typeof participantId === 'string' ? participantId : message.from)
+    const replyTo = message.metadata?.replyTo
+    if (typeof replyTo === 'string') push(this.byReplyTo, replyTo)
+  }
+
+  /**
+   * Get a message by ID
+   */
+  getMessage(id: string): Message | null {
+    return this.byId.get(id) ?? null
+  }
+
+  /**
+   * Get all messages from a sender, oldest first
+   *
+   * @param sender - Participant ID, or display name for messages without one
+   */
+  getMessagesFrom(sender: string): Message[] {
+    return [...(this.bySender.get(sender) ?? [])]
+  }
+
+  /**
+   * Get the messages replying to a message, oldest first
+   */
+  getReplies(id: string): Message[] {
+    return [...(this.byReplyTo.get(id) ?? [])]
+  }
+
+  /**
+   * Get the latest `count` messages across all rounds, oldest first
+   */
+  getLatestMessages(count: number): Message[] {
+    if (count <= 0) return []
+    const latest: Message[] = []
+    for (let i = this.rounds.length - 1; i >= 0 && latest.length < count; i--) {
+      const messages = this.rounds[i]!.messages
+      latest.unshift(...messages.slice(-(count - latest.length)))
+    }
+    return latest
+  }
+
   /**
    * Complete the current round
    */
@@ -175,18 +233,36 @@ export class RoundManager {
 
   /**
    * Get context from previous rounds for prompts
+   *
+   * Keeps the most recent messages: at most `maxMessages`, then drops the
+   * oldest until the rest fit `maxTokens` (the latest message is always
+   * kept). When messages were left out and `omittedNote` is given, it
+   * leads the context.
    */
-  getPreviousContext(maxMessages = 10): string {
-    const messages = this.getAllMessages()
-    const recentMessages = messages.slice(-maxMessages)
+  getPreviousContext(
+    maxMessages = 10,
+    options: { includeSystem?: boolean; maxTokens?: number; omittedNote?: string } = {}
+  ): string {
+    const { includeSystem = true, maxTokens, omittedNote } = options
+    const messages = this.getAllMessages().filter(m => includeSystem || m.type !== 'system')
+    const lines = messages.slice(-maxMessages).map(m => `[${m.from}]: ${m.content}`)
 
-    if (recentMessages.length === 0) {
+    if (lines.length === 0) {
       return 'No previous context.'
     }
 
-    return recentMessages
-      .map(m => `[${m.from}]: ${m.content}`)
-      .join('\n\n')
+    if (maxTokens !== undefined) {
+      let tokens = lines.reduce((sum, line) => sum + estimateTokens(line), 0)
+      while (lines.length > 1 && tokens > maxTokens) {
+        tokens -= estimateTokens(lines.shift()!)
+      }
+    }
+
+    if (omittedNote && lines.length < messages.length) {
+      lines.unshift(omittedNote)
+    }
+
+    return lines.join('\n\n')
   }
 
   /**
@@ -195,5 +271,8 @@ export class RoundManager {
   clear(): void {
     this.rounds = []
     this.currentRoundIndex = -1
+    this.byId.clear()
+    this.bySender.clear()
+    this.byReplyTo.clear()
   }
 }
@@ -22,6 +22,7 @@ export const en: TranslationKeys = {
     selectHost: 'Select the host model',
     noModelsSelected: 'No models selected',
     minModelsRequired: 'At least 2 models are required for a discussion',
+    noModelsHint: 'No models were given. Pass at least 2 entries in models, e.g. [{ "providerId": "kimi" }, { "providerId": "minimax" }]; council_models lists the available providers',
     hostRequired: 'A host must be selected',
     ready: 'Council is ready to start',
   },
@@ -39,6 +40,10 @@ export const en: TranslationKeys = {
     host: 'Host',
     noHost: 'No host selected',
     waiting: 'Waiting for responses...',
+    roundLimitReached: 'The round limit of {rounds} has been reached; the discussion is closed.',
+    commandUnknown: 'Unknown command {command}. Available commands: {commands}',
+    commandUsage: 'Usage: {usage}',
+    roundLimitChanged: 'The round limit is now {rounds} (0 means unlimited).',
   },
 
   participant: {
@@ -57,6 +62,14 @@ export const en: TranslationKeys = {
     noMessages: 'No messages yet',
     newRound: '=== Round {round} ===',
     roundComplete: 'Round {round} completed',
+    duplicateSuppressed: '{name} repeated an earlier reply; it was not posted',
+    speakersSkipped: 'Speaker limit reached; skipping {names} this round',
+    exported: 'Exported {count} messages to {path}',
+    exportReady: 'Transcript of {count} messages',
+    summaryWritten: 'Summary written to {path}',
+    modelsTested: '{passed} of {total} models responded',
+    dryRun: 'Dry run: {count} requests would be sent; no model was called',
+    earlierOmitted: '[earlier messages omitted]',
   },
 
   commands: {
@@ -84,6 +97,18 @@ export const en: TranslationKeys = {
       name: 'council_next',
       description: 'Proceed to the next round',
     },
+    export: {
+      name: 'council_export',
+      description: 'Export the discussion transcript as Markdown, HTML or plain text',
+    },
+    add: {
+      name: 'council_add',
+      description: 'Add a model to the council, including during a discussion',
+    },
+    remove: {
+      name: 'council_remove',
+      description: 'Remove a model from the council, including during a discussion',
+    },
   },
 
   errors: {
@@ -95,6 +120,14 @@ export const en: TranslationKeys = {
     modelNotFound: 'Model not found: {model}',
     apiError: 'API error: {message}',
     networkError: 'Network error: {message}',
+    allParticipantsFailed: 'All participants failed to respond this round. Check the provider configuration and API keys.',
+    nothingToExport: 'There are no messages to export yet.',
+    summaryFailed: 'Could not generate the final summary: {message}',
+    summaryWriteFailed: 'Could not write the summary to {path}: {message}',
+    providerAuthError: '{name} rejected its API key: {message}. Check the key configured for this model.',
+    cannotRemoveHost: '{name} is the host and cannot be removed.',
+    duplicateParticipant: '{name} is already in the council.',
+    removedParticipantNotFound: 'No removed participant named {name}. Use council_add to bring in a new model.',
   },
 
   prompts: {
@@ -131,5 +164,47 @@ Topic: {topic}
 Previous context: {context}
 
 Please share your thoughts on this topic.`,
+
+    introPrompt: `Topic: {topic}
+
+Before the discussion begins, introduce yourself to the council.
+{instruction}`,
+
+    elaboratePrompt: 'Your previous reply was too brief. Please elaborate with concrete reasoning.',
+
+    lateJoinPrimer: `You are joining a discussion that is already under way.
+Topic: {topic}
+Participants: {participants}
+
+Everything said so far:
+{history}`,
+
+    continuePrompt: `{prompt}
+
+Your previous reply was cut off at the length limit. It ended as follows:
+{partial}
+
+Continue exactly where it stopped, without repeating anything.`,
+
+    moderatorPrompt: `You are moderating a multi-model AI discussion.
+Topic: {topic}
+Round about to start: {round}
+Latest message: {latest}
+Participants: {candidates}
+
+Choose up to {max} participants who should respond this round, in speaking order.
+Reply with only a JSON list of their names, e.g. ["Name A", "Name B"].`,
+
+    finalSummaryPrompt: `The discussion has ended.
+Topic: {topic}
+
+Full discussion:
+{history}
+
+Write the council's final answer:
+1. The consensus the participants reached, as a direct answer to the topic
+2. The points of disagreement that remain, and who holds each position`,
+
+    pingPrompt: 'Reply with OK.',
   },
 }
@@ -8,6 +8,7 @@ import type { Locale } from '../types'
 import type { TranslationKeys, Translations } from './types'
 import { en } from './en'
 import { zh } from './zh'
+import { logger } from '../utils'
 
 /**
  * All available translations
@@ -32,7 +33,7 @@ export function setLocale(locale: Locale): void {
   if (translations[locale]) {
     currentLocale = locale
   } else {
-    console.warn(`Locale "${locale}" not found, falling back to "en"`)
+    logger.warn(`Locale "${locale}" not found, falling back to "en"`)
     currentLocale = 'en'
   }
 }
@@ -88,13 +89,16 @@ export function t(keyPath: string, params?: Record<string, string | number>): st
   }
 
   // Interpolate parameters
-  if (params) {
-    return value.replace(/\{(\w+)\}/g, (_, key) => {
-      return params[key]?.toString() ?? `{${key}}`
-    })
-  }
+  return params ? interpolate(value, params) : value
+}
 
-  return value
+/**
+ * Fill `{name}` placeholders in a template, leaving unknown ones as-is
+ */
+export function interpolate(template: string, params: Record<string, string | number>): string {
+  return template.replace(/\{(\w+)\}/g, (_, key) => {
+    return params[key]?.toString() ?? `{${key}}`
+  })
 }
 
 /**
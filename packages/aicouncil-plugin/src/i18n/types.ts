@@ -27,6 +27,7 @@ export interface TranslationKeys {
     selectHost: string
     noModelsSelected: string
     minModelsRequired: string
+    noModelsHint: string
     hostRequired: string
     ready: string
   }
@@ -45,6 +46,10 @@ export interface TranslationKeys {
     host: string
     noHost: string
     waiting: string
+    roundLimitReached: string
+    commandUnknown: string
+    commandUsage: string
+    roundLimitChanged: string
   }
 
   // Participant status
@@ -65,6 +70,14 @@ export interface TranslationKeys {
     noMessages: string
     newRound: string
     roundComplete: string
+    duplicateSuppressed: string
+    speakersSkipped: string
+    exported: string
+    exportReady: string
+    summaryWritten: string
+    modelsTested: string
+    dryRun: string
+    earlierOmitted: string
   }
 
   // Commands
@@ -93,6 +106,18 @@ export interface TranslationKeys {
       name: string
       description: string
     }
+    export: {
+      name: string
+      description: string
+    }
+    add: {
+      name: string
+      description: string
+    }
+    remove: {
+      name: string
+      description: string
+    }
   }
 
   // Errors
@@ -105,6 +130,14 @@ export interface TranslationKeys {
     modelNotFound: string
     apiError: string
     networkError: string
+    allParticipantsFailed: string
+    nothingToExport: string
+    summaryFailed: string
+    summaryWriteFailed: string
+    providerAuthError: string
+    cannotRemoveHost: string
+    duplicateParticipant: string
+    removedParticipantNotFound: string
   }
 
   // Prompts (for LLM)
@@ -113,6 +146,13 @@ export interface TranslationKeys {
     participantSystemPrompt: string
     summaryPrompt: string
     roundStartPrompt: string
+    introPrompt: string
+    elaboratePrompt: string
+    lateJoinPrimer: string
+    continuePrompt: string
+    moderatorPrompt: string
+    finalSummaryPrompt: string
+    pingPrompt: string
   }
 }
 
@@ -22,6 +22,7 @@ export const zh: TranslationKeys = {
     selectHost: '选择主持人模型',
     noModelsSelected: '未选择任何模型',
     minModelsRequired: '讨论至少需要 2 个模型参与',
+    noModelsHint: '未提供任何模型。请在 models 中至少传入 2 项，例如 [{ "providerId": "kimi" }, { "providerId": "minimax" }]；可用 council_models 查看可用的提供商',
     hostRequired: '必须选择一个主持人',
     ready: '讨论组已准备就绪',
   },
@@ -39,6 +40,10 @@ export const zh: TranslationKeys = {
     host: '主持人',
     noHost: '未选择主持人',
     waiting: '等待响应中...',
+    roundLimitReached: '已达到 {rounds} 轮的上限，讨论结束。',
+    commandUnknown: '未知命令 {command}。可用命令：{commands}',
+    commandUsage: '用法：{usage}',
+    roundLimitChanged: '轮次上限现为 {rounds}（0 表示不限）。',
   },
 
   participant: {
@@ -57,6 +62,14 @@ export const zh: TranslationKeys = {
     noMessages: '暂无消息',
     newRound: '=== 第 {round} 轮 ===',
     roundComplete: '第 {round} 轮已完成',
+    duplicateSuppressed: '{name} 重复了之前的回复，已不再发布',
+    speakersSkipped: '已达到发言人数上限，本轮跳过 {names}',
+    exported: '已导出 {count} 条消息到 {path}',
+    exportReady: '共 {count} 条消息的讨论记录',
+    summaryWritten: '总结已写入 {path}',
+    modelsTested: '{total} 个模型中有 {passed} 个响应',
+    dryRun: '试运行：将发送 {count} 个请求，未调用任何模型',
+    earlierOmitted: '[已省略较早的消息]',
   },
 
   commands: {
@@ -84,6 +97,18 @@ export const zh: TranslationKeys = {
       name: 'council_next',
       description: '进入下一轮讨论',
     },
+    export: {
+      name: 'council_export',
+      description: '将讨论记录导出为 Markdown、HTML 或纯文本',
+    },
+    add: {
+      name: 'council_add',
+      description: '向讨论组添加模型，讨论进行中也可添加',
+    },
+    remove: {
+      name: 'council_remove',
+      description: '从讨论组移除模型，讨论进行中也可移除',
+    },
   },
 
   errors: {
@@ -95,6 +120,14 @@ export const zh: TranslationKeys = {
     modelNotFound: '未找到模型：{model}',
     apiError: 'API 错误：{message}',
     networkError: '网络错误：{message}',
+    allParticipantsFailed: '本轮所有参与者均未能响应，请检查提供商配置和 API 密钥。',
+    nothingToExport: '暂无可导出的消息。',
+    summaryFailed: '无法生成最终总结：{message}',
+    summaryWriteFailed: '无法将总结写入 {path}：{message}',
+    providerAuthError: '{name} 拒绝了 API 密钥：{message}。请检查该模型配置的密钥。',
+    cannotRemoveHost: '{name} 是主持人，不能被移除。',
+    duplicateParticipant: '{name} 已在讨论组中。',
+    removedParticipantNotFound: '没有名为 {name} 的已移除参与者。如需加入新模型，请使用 council_add。',
   },
 
   prompts: {
@@ -131,5 +164,47 @@ export const zh: TranslationKeys = {
 前文背景：{context}
 
 请分享你对这个议题的看法。`,
+
+    introPrompt: `议题：{topic}
+
+在讨论开始之前，请向讨论组做自我介绍。
+{instruction}`,
+
+    elaboratePrompt: '你之前的回复过于简短，请给出具体的理由并展开说明。',
+
+    lateJoinPrimer: `你正在加入一场已经开始的讨论。
+议题：{topic}
+参与者：{participants}
+
+目前为止的全部发言：
+{history}`,
+
+    continuePrompt: `{prompt}
+
+你之前的回复因长度限制被截断，内容如下：
+{partial}
+
+请从中断处继续，不要重复已有内容。`,
+
+    moderatorPrompt: `你正在主持一场多模型 AI 讨论。
+议题：{topic}
+即将开始的轮次：{round}
+最新消息：{latest}
+参与者：{candidates}
+
+请选出本轮最多 {max} 位应当发言的参与者，并按发言顺序排列。
+只回复由他们名字组成的 JSON 列表，例如 ["Name A", "Name B"]。`,
+
+    finalSummaryPrompt: `讨论已结束。
+议题：{topic}
+
+完整讨论：
+{history}
+
+请写出委员会的最终结论：
+1. 参与者达成的共识，直接回答议题
+2. 仍存在的分歧点，以及各方的立场`,
+
+    pingPrompt: '请回复 OK。',
   },
 }
@@ -1,9 +1,14 @@
-import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
 import {
   ProviderAdapter,
   createProviderConfig,
+  resolveProxy,
+  withCustomHeaders,
   PREDEFINED_PROVIDERS,
+  DEFAULT_MAX_TOKENS,
 } from './adapter'
+import { ProviderError } from './errors'
+import { setLogLevel } from '../utils'
 import type { Participant } from '../types'
 
 describe('ProviderAdapter', () => {
@@ -35,8 +40,9 @@ describe('ProviderAdapter', () => {
 
   describe('setClient', () => {
     it('should set the client', () => {
+      expect(adapter.hasClient).toBe(false)
       adapter.setClient(mockClient as any)
-      // Client is private, we test it through call method
+      expect(adapter.hasClient).toBe(true)
     })
   })
 
@@ -82,6 +88,42 @@ describe('ProviderAdapter', () => {
         },
       })
       expect(result.content).toBe('Response text')
+      expect(result).not.toHaveProperty('usage')
+    })
+
+    it('should read token counts from the OpenCode reply', async () => {
+      vi.mocked(mockClient.session.prompt).mockResolvedValue({
+        data: {
+          info: { tokens: { input: 120, output: 30, reasoning: 10 } },
+          parts: [{ type: 'text', text: 'Response text' }],
+        },
+      })
+
+      const result = await adapter.call(mockParticipant, 'Hello')
+
+      expect(result.usage).toEqual({ inputTokens: 120, outputTokens: 40 })
+    })
+
+    it('should log request and response diagnostics at debug level', async () => {
+      const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+      setLogLevel('debug')
+      vi.mocked(mockClient.session.prompt).mockResolvedValue({
+        data: {
+          parts: [{ type: 'text', text: 'Response text' }],
+        },
+      })
+
+      try {
+        await adapter.call(mockParticipant, `Hello ${'x'.repeat(300)}`, { systemPrompt: 'Be brief' })
+      } finally {
+        setLogLevel('info')
+        errorSpy.mockRestore()
+      }
+
+      const lines = errorSpy.mock.calls.map(([line]) => String(line))
+      expect(lines[0]).toMatch(/^\[aicouncil\] debug: request participant="Test Participant" model="test-model" messages=2 prompt="Hello x+\.\.\."$/)
+      expect(lines[0].length).toBeLessThan(300)
+      expect(lines[1]).toMatch(/^\[aicouncil\] debug: response participant="Test Participant" latencyMs=\d+ text="Response text"$/)
     })
 
     it('should include system prompt when provided', async () => {
@@ -102,6 +144,23 @@ describe('ProviderAdapter', () => {
       })
     })
 
+    it('should describe the response schema to the client in the system prompt', async () => {
+      vi.mocked(mockClient.session.prompt).mockResolvedValue({
+        data: { parts: [{ type: 'text', text: '{"verdict":"yes"}' }] },
+      })
+      const schema = { type: 'object' as const, required: ['verdict'] }
+
+      await adapter.call(
+        { ...mockParticipant, provider: { ...mockParticipant.provider, responseSchema: schema } },
+        'Decide',
+        { systemPrompt: 'You are a judge', enforceSchema: true }
+      )
+
+      const { system } = vi.mocked(mockClient.session.prompt).mock.calls[0][0].body
+      expect(system[0].text).toMatch(/^You are a judge\n\nReply with only a JSON value/)
+      expect(system[0].text).toContain(JSON.stringify(schema))
+    })
+
     it('should throw error on empty response', async () => {
       vi.mocked(mockClient.session.prompt).mockResolvedValue({
         data: {
@@ -188,6 +247,44 @@ describe('ProviderAdapter', () => {
       expect(mockClient.session.prompt).toHaveBeenCalledTimes(3)
     })
 
+    it('should redact the API key from error messages', async () => {
+      const participant: Participant = {
+        ...mockParticipant,
+        provider: { ...mockParticipant.provider, apiKey: 'secret-key-1234567890' },
+      }
+      vi.mocked(mockClient.session.prompt).mockRejectedValue(
+        new Error('Unauthorized: invalid key secret-key-1234567890')
+      )
+
+      const error = await adapter.call(participant, 'Hello', { retries: 0 }).catch(e => e)
+
+      expect(error.message).toBe('Unauthorized: invalid key [REDACTED]')
+      expect(error.stack).not.toContain('secret-key-1234567890')
+    })
+
+    it('should redact the API key from the debug response line', async () => {
+      const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+      setLogLevel('debug')
+      const participant: Participant = {
+        ...mockParticipant,
+        provider: { ...mockParticipant.provider, apiKey: 'secret-key-1234567890' },
+      }
+      vi.mocked(mockClient.session.prompt).mockResolvedValue({
+        data: { parts: [{ type: 'text', text: 'Your key is secret-key-1234567890' }] },
+      })
+
+      try {
+        await adapter.call(participant, 'Hello')
+
+        const lines = errorSpy.mock.calls.map(([line]) => String(line))
+        expect(lines.some(line => line.includes('text="Your key is [REDACTED]"'))).toBe(true)
+        expect(lines.join('\n')).not.toContain('secret-key-1234567890')
+      } finally {
+        setLogLevel('info')
+        errorSpy.mockRestore()
+      }
+    })
+
     it('should throw after max retries', async () => {
       vi.mocked(mockClient.session.prompt).mockRejectedValue(
         new Error('Persistent error')
@@ -201,6 +298,711 @@ describe('ProviderAdapter', () => {
     })
   })
 
+  describe('direct API (anthropic-compatible)', () => {
+    const mockFetch = vi.fn()
+    const originalFetch = global.fetch
+
+    const kimiParticipant: Participant = {
+      ...mockParticipant,
+      provider: {
+        id: 'kimi',
+        name: 'Kimi',
+        baseURL: 'https://api.kimi.com/coding/',
+        apiKey: 'kimi-key',
+        modelId: 'kimi-for-coding',
+      },
+    }
+
+    function sseBody(events: Array<Record<string, unknown>>): ReadableStream<Uint8Array> {
+      const text = events
+        .map(e => `event: ${e.type}\ndata: ${JSON.stringify(e)}\n\n`)
+        .join('')
+      const encoder = new TextEncoder()
+      return new ReadableStream({
+        start(controller) {
+          // Split mid-line to exercise partial line buffering
+          controller.enqueue(encoder.encode(text.slice(0, 40)))
+          controller.enqueue(encoder.encode(text.slice(40)))
+          controller.close()
+        },
+      })
+    }
+
+    beforeEach(() => {
+      mockFetch.mockReset()
+      global.fetch = mockFetch
+    })
+
+    afterEach(() => {
+      global.fetch = originalFetch
+    })
+
+    it('should return the text block of a messages response', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          content: [{ type: 'text', text: 'Hello from Kimi' }],
+          usage: { input_tokens: 3, output_tokens: 4 },
+          stop_reason: 'end_turn',
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(kimiParticipant, 'Hello')
+
+      expect(mockFetch).toHaveBeenCalledWith(
+        'https://api.kimi.com/coding/v1/messages',
+        expect.objectContaining({ method: 'POST' })
+      )
+      expect(JSON.parse(mockFetch.mock.calls[0][1].body).stream).toBeUndefined()
+      expect(result.content).toBe('Hello from Kimi')
+      expect(result.usage).toEqual({ inputTokens: 3, outputTokens: 4 })
+    })
+
+    it('should join every text block and skip other block types', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          content: [
+            { type: 'text', text: 'The first half, ' },
+            { type: 'tool_use', id: 'tool-1', name: 'search', input: {} },
+            { type: 'text', text: 'and the tail.' },
+          ],
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(kimiParticipant, 'Hello')
+
+      expect(result.content).toBe('The first half, and the tail.')
+    })
+
+    it('should fail when a response has no text blocks', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          content: [{ type: 'tool_use', id: 'tool-1', name: 'search', input: {} }],
+        }),
+      })
+
+      await expect(
+        new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0 })
+      ).rejects.toThrow('Empty response from Kimi API')
+    })
+
+    it('should capture thinking blocks separately from the reply', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          content: [
+            { type: 'thinking', thinking: 'Let me weigh both options.' },
+            { type: 'text', text: 'Option A is better.' },
+          ],
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(kimiParticipant, 'Hello')
+
+      expect(result.content).toBe('Option A is better.')
+      expect(result.thinking).toBe('Let me weigh both options.')
+    })
+
+    it('should use the per-model maxTokens', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ content: [{ type: 'text', text: 'Hi' }] }),
+      })
+
+      await new ProviderAdapter().call(
+        { ...kimiParticipant, provider: { ...kimiParticipant.provider, maxTokens: 8192 } },
+        'Hello'
+      )
+
+      expect(JSON.parse(mockFetch.mock.calls[0][1].body).max_tokens).toBe(8192)
+    })
+
+    it('should fall back to the provider\'s default maxTokens', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ content: [{ type: 'text', text: 'Hi' }] }),
+      })
+
+      await new ProviderAdapter().call(kimiParticipant, 'Hello')
+
+      expect(JSON.parse(mockFetch.mock.calls[0][1].body).max_tokens).toBe(DEFAULT_MAX_TOKENS.kimi)
+    })
+
+    it('should send sampling controls only when configured', async () => {
+      mockFetch.mockResolvedValue({
+        ok: true,
+        json: async () => ({ content: [{ type: 'text', text: 'Hi' }] }),
+      })
+
+      await new ProviderAdapter().call(kimiParticipant, 'Hello')
+      await new ProviderAdapter().call(
+        { ...kimiParticipant, provider: { ...kimiParticipant.provider, temperature: 0, topP: 0.5 } },
+        'Hello'
+      )
+
+      const defaults = JSON.parse(mockFetch.mock.calls[0][1].body)
+      const tuned = JSON.parse(mockFetch.mock.calls[1][1].body)
+      expect(defaults).not.toHaveProperty('temperature')
+      expect(defaults).not.toHaveProperty('top_p')
+      expect(tuned.temperature).toBe(0)
+      expect(tuned.top_p).toBe(0.5)
+    })
+
+    it('should stream text deltas when onChunk is set', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        body: sseBody([
+          { type: 'message_start', message: { usage: { input_tokens: 7 } } },
+          { type: 'content_block_start', index: 0, content_block: { type: 'text', text: '' } },
+          { type: 'content_block_delta', index: 0, delta: { type: 'text_delta', text: 'Hello' } },
+          { type: 'content_block_delta', index: 0, delta: { type: 'text_delta', text: ' world' } },
+          { type: 'message_delta', delta: { stop_reason: 'end_turn' }, usage: { output_tokens: 2 } },
+          { type: 'message_stop' },
+        ]),
+      })
+
+      const chunks: string[] = []
+      const result = await new ProviderAdapter().call(kimiParticipant, 'Hello', {
+        onChunk: text => chunks.push(text),
+      })
+
+      expect(JSON.parse(mockFetch.mock.calls[0][1].body).stream).toBe(true)
+      expect(chunks).toEqual(['Hello', ' world'])
+      expect(result.content).toBe('Hello world')
+      expect(result.finishReason).toBe('end_turn')
+      expect(result.usage).toEqual({ inputTokens: 7, outputTokens: 2 })
+    })
+
+    it('should collect streamed thinking without forwarding it', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        body: sseBody([
+          { type: 'message_start', message: {} },
+          { type: 'content_block_start', index: 0, content_block: { type: 'thinking', thinking: '' } },
+          { type: 'content_block_delta', index: 0, delta: { type: 'thinking_delta', thinking: 'Hmm.' } },
+          { type: 'content_block_start', index: 1, content_block: { type: 'text', text: '' } },
+          { type: 'content_block_delta', index: 1, delta: { type: 'text_delta', text: 'Answer' } },
+          { type: 'message_stop' },
+        ]),
+      })
+
+      const chunks: string[] = []
+      const result = await new ProviderAdapter().call(kimiParticipant, 'Hello', {
+        onChunk: text => chunks.push(text),
+      })
+
+      expect(chunks).toEqual(['Answer'])
+      expect(result.content).toBe('Answer')
+      expect(result.thinking).toBe('Hmm.')
+    })
+
+    it('should send requests through the model\'s proxy', async () => {
+      const proxied = { ...kimiParticipant, provider: { ...kimiParticipant.provider, proxy: 'http://proxy.corp:8080' } }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ content: [{ type: 'text', text: 'Hello' }] }),
+      })
+
+      await new ProviderAdapter().call(proxied, 'Hello')
+
+      expect(mockFetch.mock.calls[0][1].proxy).toBe('http://proxy.corp:8080')
+    })
+
+    it('should send the model\'s custom headers', async () => {
+      const gateway = { ...kimiParticipant, provider: { ...kimiParticipant.provider, headers: { 'x-gateway-token': 'gw-1' } } }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ content: [{ type: 'text', text: 'Hello' }] }),
+      })
+
+      await new ProviderAdapter().call(gateway, 'Hello')
+
+      expect(mockFetch.mock.calls[0][1].headers).toMatchObject({
+        'Authorization': 'Bearer kimi-key',
+        'x-gateway-token': 'gw-1',
+      })
+    })
+
+    it('should refuse to follow redirects', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: false,
+        status: 301,
+        headers: new Headers({ location: 'https://elsewhere.example.com/v1/messages' }),
+        text: async () => '',
+      })
+
+      await expect(
+        new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0 })
+      ).rejects.toThrow('Kimi API redirected to https://elsewhere.example.com/v1/messages; check the provider base URL')
+      expect(mockFetch.mock.calls[0][1].redirect).toBe('manual')
+    })
+
+    it('should not retry a rejected API key', async () => {
+      mockFetch.mockResolvedValue({
+        ok: false,
+        status: 401,
+        text: async () => '{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}',
+      })
+
+      const error = await new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 3 }).catch(e => e)
+
+      expect(error).toMatchObject({ kind: 'auth', status: 401 })
+      expect(mockFetch).toHaveBeenCalledTimes(1)
+    })
+
+    it('should classify an unreachable host as a network error', async () => {
+      mockFetch.mockRejectedValueOnce(new TypeError('fetch failed'))
+
+      const error = await new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0 }).catch(e => e)
+
+      expect(error).toBeInstanceOf(ProviderError)
+      expect(error.kind).toBe('network')
+    })
+
+    it('should classify overloaded and rate limited responses', async () => {
+      mockFetch
+        .mockResolvedValueOnce({
+          ok: false,
+          status: 529,
+          text: async () => '{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}',
+        })
+        .mockResolvedValueOnce({
+          ok: false,
+          status: 429,
+          text: async () => '{"type":"error","error":{"type":"rate_limit_error","message":"Slow down"}}',
+        })
+
+      const overloaded = await new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0 }).catch(e => e)
+      const rateLimited = await new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0 }).catch(e => e)
+
+      expect(overloaded).toBeInstanceOf(ProviderError)
+      expect(overloaded).toMatchObject({ kind: 'overloaded', status: 529 })
+      expect(rateLimited).toMatchObject({ kind: 'rate_limit', status: 429 })
+    })
+
+    it('should surface stream error events', async () => {
+      mockFetch.mockResolvedValue({
+        ok: true,
+        body: sseBody([
+          { type: 'message_start', message: {} },
+          { type: 'error', error: { type: 'overloaded_error', message: 'Overloaded' } },
+        ]),
+      })
+
+      await expect(
+        new ProviderAdapter().call(kimiParticipant, 'Hello', { retries: 0, onChunk: () => {} })
+      ).rejects.toMatchObject({ message: 'Overloaded', kind: 'overloaded' })
+    })
+  })
+
+  describe('direct API (openai-compatible)', () => {
+    const mockFetch = vi.fn()
+    const originalFetch = global.fetch
+
+    const openaiParticipant: Participant = {
+      ...mockParticipant,
+      provider: {
+        id: 'openai',
+        name: 'GPT-4o',
+        baseURL: 'https://api.openai.com/v1/',
+        apiKey: 'openai-key',
+        modelId: 'gpt-4o',
+      },
+    }
+
+    beforeEach(() => {
+      mockFetch.mockReset()
+      global.fetch = mockFetch
+    })
+
+    afterEach(() => {
+      global.fetch = originalFetch
+    })
+
+    it('should call chat completions with the system prompt first', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          choices: [{ message: { role: 'assistant', content: 'Hello from GPT' }, finish_reason: 'stop' }],
+          usage: { prompt_tokens: 5, completion_tokens: 3, total_tokens: 8 },
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(openaiParticipant, 'Hello', {
+        systemPrompt: 'Be brief',
+      })
+
+      expect(mockFetch).toHaveBeenCalledWith(
+        'https://api.openai.com/v1/chat/completions',
+        expect.objectContaining({ method: 'POST' })
+      )
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body.messages).toEqual([
+        { role: 'system', content: 'Be brief' },
+        { role: 'user', content: 'Hello' },
+      ])
+      expect(result.content).toBe('Hello from GPT')
+      expect(result).not.toHaveProperty('thinking')
+      expect(result.usage).toEqual({ inputTokens: 5, outputTokens: 3, totalTokens: 8 })
+    })
+
+    it('should adapt token and sampling params for reasoning models', async () => {
+      const reply = {
+        ok: true,
+        json: async () => ({ choices: [{ message: { content: 'Hi' } }] }),
+      }
+      mockFetch.mockResolvedValue(reply)
+      const tuned = (modelId: string): Participant => ({
+        ...openaiParticipant,
+        provider: { ...openaiParticipant.provider, modelId, maxTokens: 500, temperature: 0.2, topP: 0.9 },
+      })
+
+      await new ProviderAdapter().call(tuned('o1'), 'Hello')
+      await new ProviderAdapter().call(tuned('o3-mini'), 'Hello')
+      await new ProviderAdapter().call(tuned('gpt-4o'), 'Hello')
+
+      const [o1, o3, gpt] = mockFetch.mock.calls.map(call => JSON.parse(call[1].body))
+      for (const body of [o1, o3]) {
+        expect(body.max_completion_tokens).toBe(500)
+        expect(body).not.toHaveProperty('max_tokens')
+        expect(body).not.toHaveProperty('temperature')
+        expect(body).not.toHaveProperty('top_p')
+      }
+      expect(gpt.max_tokens).toBe(500)
+      expect(gpt).not.toHaveProperty('max_completion_tokens')
+      expect(gpt.temperature).toBe(0.2)
+      expect(gpt.top_p).toBe(0.9)
+    })
+
+    it('should request and validate structured replies', async () => {
+      const schema = {
+        type: 'object' as const,
+        properties: { verdict: { type: 'string' as const, enum: ['yes', 'no'] } },
+        required: ['verdict'],
+      }
+      const reply = (content: string) => ({
+        ok: true,
+        json: async () => ({ choices: [{ message: { content } }] }),
+      })
+      mockFetch.mockResolvedValueOnce(reply('{"verdict":"yes"}'))
+
+      const result = await new ProviderAdapter().call(
+        { ...openaiParticipant, provider: { ...openaiParticipant.provider, responseSchema: schema } },
+        'Decide',
+        { enforceSchema: true }
+      )
+
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body.response_format).toEqual({
+        type: 'json_schema',
+        json_schema: { name: 'reply', schema },
+      })
+      expect(body.messages[0].role).toBe('system')
+      expect(body.messages[0].content).toContain(JSON.stringify(schema))
+      expect(result.content).toBe('{"verdict":"yes"}')
+      expect(mockFetch).toHaveBeenCalledTimes(1)
+    })
+
+    it('should leave calls without enforceSchema unstructured', async () => {
+      const schema = { type: 'object' as const, required: ['verdict'] }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ choices: [{ message: { content: 'Plain text' } }] }),
+      })
+
+      const result = await new ProviderAdapter().call(
+        { ...openaiParticipant, provider: { ...openaiParticipant.provider, responseSchema: schema } },
+        'Pick the next speaker',
+        { systemPrompt: 'Be brief' }
+      )
+
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body).not.toHaveProperty('response_format')
+      expect(body.messages[0].content).toBe('Be brief')
+      expect(result.content).toBe('Plain text')
+      expect(mockFetch).toHaveBeenCalledTimes(1)
+    })
+
+    it('should mark the schema strict only when asked', async () => {
+      const schema = { type: 'object' as const, required: ['verdict'] }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ choices: [{ message: { content: '{"verdict":"yes"}' } }] }),
+      })
+
+      await new ProviderAdapter().call(
+        { ...openaiParticipant, provider: { ...openaiParticipant.provider, responseSchema: schema, strictSchema: true } },
+        'Decide',
+        { enforceSchema: true }
+      )
+
+      expect(JSON.parse(mockFetch.mock.calls[0][1].body).response_format.json_schema.strict).toBe(true)
+    })
+
+    it('should retry once when a structured reply does not conform', async () => {
+      const schema = { type: 'object' as const, required: ['verdict'] }
+      const structured = { ...openaiParticipant, provider: { ...openaiParticipant.provider, responseSchema: schema } }
+      const reply = (content: string) => ({
+        ok: true,
+        json: async () => ({ choices: [{ message: { content } }] }),
+      })
+
+      mockFetch
+        .mockResolvedValueOnce(reply('{"answer":"yes"}'))
+        .mockResolvedValueOnce(reply('```json\n{"verdict":"yes"}\n```'))
+      const retried = await new ProviderAdapter().call(structured, 'Decide', { enforceSchema: true })
+      expect(mockFetch).toHaveBeenCalledTimes(2)
+      expect(retried.content).toContain('"verdict"')
+
+      mockFetch.mockReset()
+      mockFetch.mockResolvedValue(reply('not json'))
+      await expect(new ProviderAdapter().call(structured, 'Decide', { enforceSchema: true })).rejects.toThrow(
+        'Reply from Test Participant does not match the response schema: reply is not valid JSON'
+      )
+    })
+
+    it('should keep reasoning_content apart from the reply', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          choices: [{
+            message: { role: 'assistant', content: 'The answer is 4.', reasoning_content: '2 + 2 = 4' },
+            finish_reason: 'stop',
+          }],
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(openaiParticipant, 'What is 2 + 2?')
+
+      expect(result.content).toBe('The answer is 4.')
+      expect(result.thinking).toBe('2 + 2 = 4')
+    })
+
+    it('should call an Azure deployment with its api-key header', async () => {
+      const azureParticipant: Participant = {
+        ...mockParticipant,
+        provider: {
+          id: 'azure',
+          name: 'Azure GPT-4o',
+          baseURL: 'https://my-resource.openai.azure.com/',
+          apiKey: 'azure-key',
+          modelId: 'gpt-4o',
+          deployment: 'gpt4o-prod',
+        },
+      }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({ choices: [{ message: { content: 'Hello from Azure' }, finish_reason: 'stop' }] }),
+      })
+
+      const result = await new ProviderAdapter().call(azureParticipant, 'Hello', { systemPrompt: 'Be brief' })
+
+      const [url, init] = mockFetch.mock.calls[0]
+      expect(url).toBe(
+        'https://my-resource.openai.azure.com/openai/deployments/gpt4o-prod/chat/completions?api-version=2024-10-21'
+      )
+      expect(init.headers['api-key']).toBe('azure-key')
+      expect(init.headers).not.toHaveProperty('Authorization')
+      expect(JSON.parse(init.body).messages).toEqual([
+        { role: 'system', content: 'Be brief' },
+        { role: 'user', content: 'Hello' },
+      ])
+      expect(result.content).toBe('Hello from Azure')
+    })
+
+    it('should call DeepSeek on its own endpoint in JSON mode', async () => {
+      const deepseekParticipant: Participant = {
+        ...mockParticipant,
+        provider: {
+          ...PREDEFINED_PROVIDERS.deepseek('deepseek-key', 'deepseek-reasoner'),
+          responseSchema: { type: 'object' },
+        },
+      }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          choices: [{
+            message: { role: 'assistant', content: '{"answer":4}', reasoning_content: 'Add them.' },
+            finish_reason: 'stop',
+          }],
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(deepseekParticipant, 'What is 2 + 2?', { enforceSchema: true })
+
+      expect(mockFetch).toHaveBeenCalledWith(
+        'https://api.deepseek.com/chat/completions',
+        expect.objectContaining({ method: 'POST' })
+      )
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body.model).toBe('deepseek-reasoner')
+      expect(body.response_format).toEqual({ type: 'json_object' })
+      expect(body.messages[0].content).toContain('{"type":"object"}')
+      expect(result.content).toBe('{"answer":4}')
+      expect(result.thinking).toBe('Add them.')
+    })
+  })
+
+  describe('direct API (ollama)', () => {
+    const mockFetch = vi.fn()
+    const originalFetch = global.fetch
+
+    const ollamaParticipant: Participant = {
+      ...mockParticipant,
+      provider: PREDEFINED_PROVIDERS.ollama(),
+    }
+
+    beforeEach(() => {
+      mockFetch.mockReset()
+      global.fetch = mockFetch
+    })
+
+    afterEach(() => {
+      global.fetch = originalFetch
+    })
+
+    it('should post to /api/chat without an auth header when there is no key', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        text: async () => JSON.stringify({
+          message: { role: 'assistant', content: 'Hello from llama' },
+          done: true,
+          done_reason: 'stop',
+          prompt_eval_count: 12,
+          eval_count: 4,
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(ollamaParticipant, 'Hello', { systemPrompt: 'Be brief' })
+
+      const [url, init] = mockFetch.mock.calls[0]
+      expect(url).toBe('http://localhost:11434/api/chat')
+      expect(init.headers).not.toHaveProperty('Authorization')
+      const body = JSON.parse(init.body)
+      expect(body.model).toBe('llama3.2')
+      expect(body.messages).toEqual([
+        { role: 'system', content: 'Be brief' },
+        { role: 'user', content: 'Hello' },
+      ])
+      expect(result.content).toBe('Hello from llama')
+      expect(result.usage).toEqual({ inputTokens: 12, outputTokens: 4 })
+      expect(result.finishReason).toBe('stop')
+    })
+
+    it('should concatenate streamed NDJSON chunks', async () => {
+      const keyed = { ...ollamaParticipant, provider: { ...ollamaParticipant.provider, apiKey: 'proxy-key' } }
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        text: async () => [
+          { message: { content: 'Hel' }, done: false },
+          { message: { content: 'lo' }, done: false },
+          { message: { content: '' }, done: true, done_reason: 'length', eval_count: 2 },
+        ].map(chunk => JSON.stringify(chunk)).join('\n') + '\n',
+      })
+
+      const result = await new ProviderAdapter().call(keyed, 'Hello')
+
+      expect(mockFetch.mock.calls[0][1].headers.Authorization).toBe('Bearer proxy-key')
+      expect(result.content).toBe('Hello')
+      expect(result.finishReason).toBe('length')
+      expect(result.usage?.outputTokens).toBe(2)
+    })
+  })
+
+  describe('direct API (google)', () => {
+    const mockFetch = vi.fn()
+    const originalFetch = global.fetch
+
+    const geminiParticipant: Participant = {
+      ...mockParticipant,
+      provider: {
+        id: 'google',
+        name: 'Gemini',
+        baseURL: 'https://generativelanguage.googleapis.com',
+        apiKey: 'gemini-key',
+        modelId: 'gemini-2.5-flash',
+      },
+    }
+
+    beforeEach(() => {
+      mockFetch.mockReset()
+      global.fetch = mockFetch
+    })
+
+    afterEach(() => {
+      global.fetch = originalFetch
+    })
+
+    it('should call generateContent with the key as a query parameter', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          candidates: [{
+            content: { role: 'model', parts: [{ text: 'Hello from Gemini' }] },
+            finishReason: 'STOP',
+          }],
+        }),
+      })
+
+      const result = await new ProviderAdapter().call(geminiParticipant, 'Hello', {
+        systemPrompt: 'Be brief',
+      })
+
+      expect(mockFetch).toHaveBeenCalledWith(
+        'https://generativelanguage.googleapis.com/v1beta/models/gemini-2.5-flash:generateContent?key=gemini-key',
+        expect.objectContaining({ method: 'POST' })
+      )
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body.contents).toEqual([{ role: 'user', parts: [{ text: 'Hello' }] }])
+      expect(body.systemInstruction).toEqual({ parts: [{ text: 'Be brief' }] })
+      expect(result.content).toBe('Hello from Gemini')
+      expect(result.finishReason).toBe('STOP')
+    })
+
+    it('should surface non-200 responses as errors', async () => {
+      mockFetch.mockResolvedValue({
+        ok: false,
+        status: 403,
+        text: async () => 'API key not valid',
+      })
+
+      await expect(
+        new ProviderAdapter().call(geminiParticipant, 'Hello', { retries: 0 })
+      ).rejects.toThrow('Gemini API error: 403 - API key not valid')
+    })
+
+    it('should send the response schema in Gemini\'s format', async () => {
+      mockFetch.mockResolvedValueOnce({
+        ok: true,
+        json: async () => ({
+          candidates: [{ content: { role: 'model', parts: [{ text: '{"verdict":"yes"}' }] }, finishReason: 'STOP' }],
+        }),
+      })
+
+      await new ProviderAdapter().call({
+        ...geminiParticipant,
+        provider: {
+          ...geminiParticipant.provider,
+          responseSchema: {
+            type: 'object',
+            properties: { verdict: { type: 'string', enum: ['yes', 'no'] }, note: { type: 'null' } },
+            required: ['verdict'],
+          },
+        },
+      }, 'Decide', { enforceSchema: true })
+
+      const body = JSON.parse(mockFetch.mock.calls[0][1].body)
+      expect(body.generationConfig.responseMimeType).toBe('application/json')
+      expect(body.generationConfig.responseSchema).toEqual({
+        type: 'OBJECT',
+        properties: { verdict: { type: 'STRING', enum: ['yes', 'no'] }, note: { nullable: true } },
+        required: ['verdict'],
+      })
+    })
+  })
+
   describe('callParallel', () => {
     const participants: Participant[] = [
       {
@@ -409,4 +1211,101 @@ describe('PREDEFINED_PROVIDERS', () => {
       expect(config.modelId).toBe('gpt-4o-mini')
     })
   })
+
+  describe('google', () => {
+    it('should create Gemini provider config with default model', () => {
+      const config = PREDEFINED_PROVIDERS.google('test-api-key')
+
+      expect(config.id).toBe('google')
+      expect(config.name).toBe('Gemini')
+      expect(config.baseURL).toBe('https://generativelanguage.googleapis.com')
+      expect(config.modelId).toBe('gemini-2.5-pro')
+    })
+
+    it('should create Gemini provider config with custom model', () => {
+      const config = PREDEFINED_PROVIDERS.google('test-api-key', 'gemini-2.5-flash')
+
+      expect(config.modelId).toBe('gemini-2.5-flash')
+    })
+  })
+
+  describe('ollama', () => {
+    it('should create Ollama provider config without an API key', () => {
+      const config = PREDEFINED_PROVIDERS.ollama()
+
+      expect(config.id).toBe('ollama')
+      expect(config.baseURL).toBe('http://localhost:11434')
+      expect(config.apiKey).toBe('')
+      expect(config.modelId).toBe('llama3.2')
+    })
+  })
+
+  describe('deepseek', () => {
+    it('should create DeepSeek provider config with default model', () => {
+      const config = PREDEFINED_PROVIDERS.deepseek('test-api-key')
+
+      expect(config.id).toBe('deepseek')
+      expect(config.name).toBe('DeepSeek')
+      expect(config.baseURL).toBe('https://api.deepseek.com')
+      expect(config.modelId).toBe('deepseek-chat')
+    })
+
+    it('should create DeepSeek provider config with the reasoner model', () => {
+      const config = PREDEFINED_PROVIDERS.deepseek('test-api-key', 'deepseek-reasoner')
+
+      expect(config.modelId).toBe('deepseek-reasoner')
+    })
+  })
+})
+
+describe('resolveProxy', () => {
+  afterEach(() => {
+    vi.unstubAllEnvs()
+  })
+
+  it('should prefer the configured proxy', () => {
+    vi.stubEnv('HTTPS_PROXY', 'http://env-proxy:3128')
+
+    expect(resolveProxy('https://api.kimi.com/coding/v1/messages', 'http://proxy.corp:8080')).toBe('http://proxy.corp:8080')
+  })
+
+  it('should fall back to HTTPS_PROXY unless NO_PROXY matches the host', () => {
+    vi.stubEnv('HTTPS_PROXY', 'http://env-proxy:3128')
+    vi.stubEnv('NO_PROXY', 'localhost, .internal.example.com')
+
+    expect(resolveProxy('https://api.kimi.com/coding/v1/messages')).toBe('http://env-proxy:3128')
+    expect(resolveProxy('http://localhost:11434/api/chat')).toBeUndefined()
+    expect(resolveProxy('https://llm.internal.example.com/v1/chat/completions')).toBeUndefined()
+  })
+
+  it('should use no proxy when none is configured', () => {
+    vi.stubEnv('HTTPS_PROXY', '')
+    vi.stubEnv('https_proxy', '')
+    vi.stubEnv('HTTP_PROXY', '')
+    vi.stubEnv('http_proxy', '')
+
+    expect(resolveProxy('https://api.kimi.com/coding/v1/messages')).toBeUndefined()
+  })
+})
+
+describe('withCustomHeaders', () => {
+  it('should add custom headers after the built-in ones', () => {
+    expect(withCustomHeaders({ 'Authorization': 'Bearer key' }, { headers: { 'OpenAI-Organization': 'org-1' } })).toEqual({
+      'Authorization': 'Bearer key',
+      'OpenAI-Organization': 'org-1',
+    })
+  })
+
+  it('should keep built-in headers unless overriding is allowed', () => {
+    const warnSpy = vi.spyOn(console, 'warn').mockImplementation(() => {})
+    const headers = { authorization: 'Bearer gateway' }
+
+    expect(withCustomHeaders({ 'Authorization': 'Bearer key' }, { headers })).toEqual({ 'Authorization': 'Bearer key' })
+    expect(warnSpy).toHaveBeenCalled()
+    expect(withCustomHeaders({ 'Authorization': 'Bearer key' }, { headers, overrideHeaders: true })).toEqual({
+      authorization: 'Bearer gateway',
+    })
+
+    warnSpy.mockRestore()
+  })
 })
@@ -6,68 +6,153 @@
  * Falls back to direct API calls when no OpenCode client is available
  */
 
-import type { ProviderConfig, Participant } from '../types'
+import type { ProviderConfig, Participant, JsonSchema } from '../types'
 import { t } from '../i18n'
-import { timeout, retry } from '../utils'
+import { timeout, retry, redactSecrets, validateJsonSchema, truncate, logger } from '../utils'
+import { ProviderError, classifyProviderError, toProviderError, isRetryable } from './errors'
+import { acquireRequestSlot, rateLimitKey } from './rate-limit'
 
 /**
- * Call Kimi API directly (Anthropic-compatible endpoint)
+ * Anthropic Messages API response body
+ */
+interface AnthropicResponse {
+  content?: Array<{ type: string; text?: string; thinking?: string }>
+  usage?: { input_tokens?: number; output_tokens?: number }
+  stop_reason?: string
+}
+
+/**
+ * Reply token limit per provider, used when neither the call nor the
+ * model sets `maxTokens`
+ *
+ * Anthropic-style endpoints require a limit and their models tend to
+ * write long replies, so they get more room than the generic fallback.
+ */
+export const DEFAULT_MAX_TOKENS: Record<string, number> = {
+  anthropic: 4096,
+  kimi: 4096,
+  minimax: 4096,
+  openai: 4096,
+  azure: 4096,
+  deepseek: 4096,
+  google: 8192,
+  ollama: 2048,
+}
+
+/** Limit for providers missing from DEFAULT_MAX_TOKENS */
+const FALLBACK_MAX_TOKENS = 2000
+
+/**
+ * Default reply token limit for a provider
+ */
+export function defaultMaxTokens(providerId: string): number {
+  return DEFAULT_MAX_TOKENS[providerId] ?? FALLBACK_MAX_TOKENS
+}
+
+/**
+ * Reject redirect responses
+ *
+ * Requests are sent with `redirect: 'manual'` so a misconfigured base URL
+ * can't replay the API key to another host; the redirect is reported as a
+ * configuration error instead.
  */
-async function callKimiAPI(
+function assertNotRedirected(label: string, response: Response): void {
+  if (response.type === 'opaqueredirect' || (response.status >= 300 && response.status < 400)) {
+    const location = response.headers?.get('location')
+    throw new Error(
+      `${label} API redirected${location ? ` to ${location}` : ''}; check the provider base URL`
+    )
+  }
+}
+
+/**
+ * Call an Anthropic-compatible messages endpoint directly
+ *
+ * When `onChunk` is set the request is streamed and each text delta is
+ * passed to it as it arrives; the full reply is still returned.
+ */
+async function callAnthropicCompatibleAPI(
+  label: string,
+  endpoint: string,
   apiKey: string,
   modelId: string,
   prompt: string,
   options: ModelCallOptions = {}
 ): Promise<ModelResponse> {
-  const { systemPrompt, timeout: timeoutMs = 60000 } = options
+  const { systemPrompt, timeout: timeoutMs = 60000, onChunk } = options
 
   const controller = new AbortController()
   const timeoutId = setTimeout(() => controller.abort(), timeoutMs)
 
   try {
-    // Kimi uses Anthropic-compatible endpoint for Claude Code
-    const response = await fetch('https://api.kimi.com/coding/v1/messages', {
+    const response = await fetch(endpoint, {
       method: 'POST',
-      headers: {
+      headers: withCustomHeaders({
         'Content-Type': 'application/json',
         'Authorization': `Bearer ${apiKey}`,
         'anthropic-version': '2023-06-01',
-      },
+      }, options),
       body: JSON.stringify({
         model: modelId,
         messages: [{ role: 'user', content: prompt }],
         system: systemPrompt,
-        max_tokens: options.maxTokens ?? 2000,
-        temperature: options.temperature ?? 0.7,
+        max_tokens: options.maxTokens ?? FALLBACK_MAX_TOKENS,
+        ...(options.temperature !== undefined && { temperature: options.temperature }),
+        ...(options.topP !== undefined && { top_p: options.topP }),
+        ...(onChunk && { stream: true }),
       }),
       signal: controller.signal,
+      redirect: 'manual',
+      proxy: resolveProxy(endpoint, options.proxy),
     })
 
-    clearTimeout(timeoutId)
+    if (!onChunk) {
+      clearTimeout(timeoutId)
+    }
+
+    assertNotRedirected(label, response)
 
     if (!response.ok) {
       const error = await response.text()
-      throw new Error(`Kimi API error: ${response.status} - ${error}`)
+      throw new ProviderError(
+        `${label} API error: ${response.status} - ${error}`,
+        classifyProviderError(response.status, error),
+        response.status
+      )
     }
 
-    const data = await response.json() as {
-      content?: Array<{ type: string; text?: string }>
-      usage?: { input_tokens?: number; output_tokens?: number }
-      stop_reason?: string
-    }
-    const content = data.content?.[0]?.text
+    const data = onChunk
+      ? await readAnthropicStream(response, onChunk)
+      : await response.json() as AnthropicResponse
+
+    clearTimeout(timeoutId)
+
+    // Join every text block; long replies may be split, and thinking or
+    // tool blocks are not part of the reply
+    const content = data.content
+      ?.filter(c => !c.type || c.type === 'text')
+      .map(c => c.text ?? '')
+      .join('')
 
     if (!content) {
-      throw new Error('Empty response from Kimi API')
+      throw new Error(`Empty response from ${label} API`)
     }
 
+    // Extended thinking is kept apart from the reply so it never enters the discussion
+    const thinking = data.content
+      ?.filter(c => c.type === 'thinking' && c.thinking)
+      .map(c => c.thinking)
+      .join('\n\n')
+
     return {
       content,
+      ...(thinking && { thinking }),
       usage: {
         inputTokens: data.usage?.input_tokens,
         outputTokens: data.usage?.output_tokens,
       },
       finishReason: data.stop_reason,
+      raw: data,
     }
   } catch (error) {
     clearTimeout(timeoutId)
@@ -75,66 +160,497 @@ async function callKimiAPI(
   }
 }
 
+/**
+ * Read an Anthropic server-sent event stream into a response body,
+ * forwarding text deltas as they arrive
+ */
+async function readAnthropicStream(
+  response: Response,
+  onChunk: (text: string) => void
+): Promise<AnthropicResponse> {
+  const result: AnthropicResponse = { content: [], usage: {} }
+  const blocks = new Map<number, { type: string; text?: string; thinking?: string }>()
+
+  if (!response.body) {
+    throw new Error('Streaming response has no body')
+  }
+
+  const reader = response.body.getReader()
+  const decoder = new TextDecoder()
+  let buffer = ''
+  let done = false
+
+  while (!done) {
+    const chunk = await reader.read()
+    if (chunk.done) break
+    buffer += decoder.decode(chunk.value, { stream: true })
+
+    // Only parse complete lines; keep any partial line for the next read
+    const lines = buffer.split('\n')
+    buffer = lines.pop() ?? ''
+
+    for (const line of lines) {
+      if (!line.startsWith('data:')) continue
+      const payload = line.slice(5).trim()
+      if (!payload) continue
+
+      const event = JSON.parse(payload) as {
+        type: string
+        index?: number
+        message?: { usage?: { input_tokens?: number } }
+        content_block?: { type: string; text?: string; thinking?: string }
+        delta?: { type?: string; text?: string; thinking?: string; stop_reason?: string }
+        usage?: { output_tokens?: number }
+        error?: { type?: string; message?: string }
+      }
+
+      switch (event.type) {
+        case 'message_start':
+          result.usage!.input_tokens = event.message?.usage?.input_tokens
+          break
+        case 'content_block_start':
+          blocks.set(event.index ?? blocks.size, { ...event.content_block!, text: event.content_block?.text ?? '' })
+          break
+        case 'content_block_delta':
+          if (event.delta?.type === 'thinking_delta' && event.delta.thinking) {
+            const block = blocks.get(event.index ?? 0) ?? { type: 'thinking', thinking: '' }
+            block.thinking = (block.thinking ?? '') + event.delta.thinking
+            blocks.set(event.index ?? 0, block)
+            break
+          }
+          if (event.delta?.type === 'text_delta' && event.delta.text) {
+            const block = blocks.get(event.index ?? 0) ?? { type: 'text', text: '' }
+            block.text = (block.text ?? '') + event.delta.text
+            blocks.set(event.index ?? 0, block)
+            onChunk(event.delta.text)
+          }
+          break
+        case 'message_delta':
+          result.stop_reason = event.delta?.stop_reason
+          result.usage!.output_tokens = event.usage?.output_tokens
+          break
+        case 'message_stop':
+          done = true
+          break
+        case 'error':
+          throw new ProviderError(
+            event.error?.message ?? 'Stream error',
+            classifyProviderError(undefined, event.error?.type)
+          )
+      }
+    }
+  }
+
+  if (done) {
+    await reader.cancel()
+  }
+
+  result.content = [...blocks.entries()]
+    .sort(([a], [b]) => a - b)
+    .map(([, block]) => block)
+  return result
+}
+
+/**
+ * Call Kimi API directly (Anthropic-compatible endpoint)
+ */
+function callKimiAPI(
+  apiKey: string,
+  modelId: string,
+  prompt: string,
+  options: ModelCallOptions = {}
+): Promise<ModelResponse> {
+  // Kimi uses Anthropic-compatible endpoint for Claude Code
+  return callAnthropicCompatibleAPI('Kimi', 'https://api.kimi.com/coding/v1/messages', apiKey, modelId, prompt, options)
+}
+
 /**
  * Call MiniMax API directly (Anthropic-compatible endpoint)
  */
-async function callMiniMaxAPI(
+function callMiniMaxAPI(
   apiKey: string,
   modelId: string,
   prompt: string,
   options: ModelCallOptions = {}
+): Promise<ModelResponse> {
+  return callAnthropicCompatibleAPI('MiniMax', 'https://api.minimaxi.com/anthropic/v1/messages', apiKey, modelId, prompt, options)
+}
+
+/**
+ * Add a model's custom headers to a request's built-in headers
+ *
+ * Custom headers are applied last. One that names a built-in header
+ * (auth, content type, API version), compared case-insensitively, is
+ * dropped with a warning unless `overrideHeaders` is set.
+ */
+export function withCustomHeaders(
+  builtIn: Record<string, string>,
+  options: Pick<ModelCallOptions, 'headers' | 'overrideHeaders'>
+): Record<string, string> {
+  const headers = { ...builtIn }
+  for (const [name, value] of Object.entries(options.headers ?? {})) {
+    const existing = Object.keys(headers).find(key => key.toLowerCase() === name.toLowerCase())
+    if (existing && !options.overrideHeaders) {
+      logger.warn('custom header ignored; set overrideHeaders to replace a built-in header', { header: name })
+      continue
+    }
+    if (existing) {
+      delete headers[existing]
+    }
+    headers[name] = value
+  }
+  return headers
+}
+
+/**
+ * Pick the proxy for a provider request
+ *
+ * A model's own proxy wins; otherwise HTTPS_PROXY / HTTP_PROXY apply
+ * unless the host matches NO_PROXY. The URL is passed as fetch's `proxy`
+ * option, which Bun (the OpenCode plugin runtime) supports for HTTP(S)
+ * proxies.
+ */
+export function resolveProxy(url: string, proxy?: string): string | undefined {
+  if (proxy) return proxy
+
+  const env = process.env
+  const fromEnv = env.HTTPS_PROXY ?? env.https_proxy ?? env.HTTP_PROXY ?? env.http_proxy
+  if (!fromEnv) return undefined
+
+  const host = new URL(url).hostname.toLowerCase()
+  const bypass = (env.NO_PROXY ?? env.no_proxy ?? '')
+    .split(',')
+    .map(entry => entry.trim().toLowerCase().replace(/:\d+$/, ''))
+    .filter(Boolean)
+    .some(entry => entry === '*' || host === entry.replace(/^\./, '') || host.endsWith(entry.startsWith('.') ? entry : `.${entry}`))
+
+  return bypass ? undefined : fromEnv
+}
+
+/**
+ * Where to send an OpenAI-compatible chat completions request
+ */
+interface ChatCompletionsEndpoint {
+  url: string
+  /** Auth headers for the endpoint */
+  headers: Record<string, string>
+}
+
+/**
+ * Endpoint for OpenAI and services that mirror its URL and Bearer auth
+ */
+function openAIEndpoint(baseURL: string, apiKey: string): ChatCompletionsEndpoint {
+  return {
+    url: `${baseURL.replace(/\/+$/, '')}/chat/completions`,
+    headers: { 'Authorization': `Bearer ${apiKey}` },
+  }
+}
+
+/**
+ * Default Azure OpenAI REST API version
+ */
+const AZURE_API_VERSION = '2024-10-21'
+
+/**
+ * Endpoint for an Azure OpenAI deployment, which is addressed by
+ * deployment name and API version and authenticated with an `api-key` header
+ */
+function azureEndpoint(provider: ProviderConfig): ChatCompletionsEndpoint {
+  const deployment = encodeURIComponent(provider.deployment ?? provider.modelId)
+  const apiVersion = encodeURIComponent(provider.apiVersion ?? AZURE_API_VERSION)
+  return {
+    url: `${provider.baseURL.replace(/\/+$/, '')}/openai/deployments/${deployment}/chat/completions?api-version=${apiVersion}`,
+    headers: { 'api-key': provider.apiKey },
+  }
+}
+
+/**
+ * OpenAI reasoning models, which take `max_completion_tokens` instead of
+ * `max_tokens` and reject non-default sampling controls
+ */
+const OPENAI_REASONING_MODEL = /^(o1|o3|o4)(-|$)/i
+
+/**
+ * Call an OpenAI-compatible chat completions endpoint directly
+ *
+ * Endpoints such as DeepSeek return their reasoning in a separate
+ * `reasoning_content` field; it is kept as `thinking` and never part of
+ * the reply. Plain OpenAI responses don't carry the field.
+ */
+async function callOpenAICompatibleAPI(
+  label: string,
+  endpoint: ChatCompletionsEndpoint,
+  modelId: string,
+  prompt: string,
+  options: ModelCallOptions = {}
 ): Promise<ModelResponse> {
   const { systemPrompt, timeout: timeoutMs = 60000 } = options
+  const reasoning = OPENAI_REASONING_MODEL.test(modelId)
+  const maxTokens = options.maxTokens ?? FALLBACK_MAX_TOKENS
+  const sampling = {
+    ...(options.temperature !== undefined && { temperature: options.temperature }),
+    ...(options.topP !== undefined && { top_p: options.topP }),
+  }
+  if (reasoning && Object.keys(sampling).length > 0) {
+    logger.debug('dropped unsupported params', { model: modelId, params: Object.keys(sampling) })
+  }
 
   const controller = new AbortController()
   const timeoutId = setTimeout(() => controller.abort(), timeoutMs)
 
   try {
-    // MiniMax uses Anthropic-compatible endpoint
-    const response = await fetch('https://api.minimaxi.com/anthropic/v1/messages', {
+    const response = await fetch(endpoint.url, {
       method: 'POST',
-      headers: {
+      headers: withCustomHeaders({
         'Content-Type': 'application/json',
-        'Authorization': `Bearer ${apiKey}`,
-        'anthropic-version': '2023-06-01',
+        ...endpoint.headers,
+      }, options),
+      body: JSON.stringify({
+        model: modelId,
+        messages: [
+          ...(systemPrompt ? [{ role: 'system', content: systemPrompt }] : []),
+          { role: 'user', content: prompt },
+        ],
+        ...(reasoning ? { max_completion_tokens: maxTokens } : { max_tokens: maxTokens, ...sampling }),
+        ...(options.responseSchema && {
+          response_format: options.responseFormat === 'json_object'
+            ? { type: 'json_object' }
+            : {
+                type: 'json_schema',
+                json_schema: {
+                  name: 'reply',
+                  schema: options.responseSchema,
+                  ...(options.strictSchema && { strict: true }),
+                },
+              },
+        }),
+      }),
+      signal: controller.signal,
+      redirect: 'manual',
+      proxy: resolveProxy(endpoint.url, options.proxy),
+    })
+
+    clearTimeout(timeoutId)
+
+    assertNotRedirected(label, response)
+
+    if (!response.ok) {
+      const error = await response.text()
+      throw new ProviderError(
+        `${label} API error: ${response.status} - ${error}`,
+        classifyProviderError(response.status, error),
+        response.status
+      )
+    }
+
+    const data = await response.json() as {
+      choices?: Array<{
+        message?: { content?: string | null; reasoning_content?: string | null }
+        finish_reason?: string
+      }>
+      usage?: { prompt_tokens?: number; completion_tokens?: number; total_tokens?: number }
+    }
+    const choice = data.choices?.[0]
+    const content = choice?.message?.content
+    const thinking = choice?.message?.reasoning_content
+
+    if (!content) {
+      throw new Error(`Empty response from ${label} API`)
+    }
+
+    return {
+      content,
+      ...(thinking && { thinking }),
+      usage: {
+        inputTokens: data.usage?.prompt_tokens,
+        outputTokens: data.usage?.completion_tokens,
+        totalTokens: data.usage?.total_tokens,
       },
+      finishReason: choice?.finish_reason,
+      raw: data,
+    }
+  } catch (error) {
+    clearTimeout(timeoutId)
+    throw error
+  }
+}
+
+/**
+ * Call a local Ollama server's chat endpoint directly
+ *
+ * No auth header is sent unless a key is configured. The request asks for
+ * a single JSON reply, but the body is read as NDJSON so servers that
+ * stream `done: false` chunks anyway are concatenated into one reply.
+ */
+async function callOllamaAPI(
+  baseURL: string,
+  apiKey: string,
+  modelId: string,
+  prompt: string,
+  options: ModelCallOptions = {}
+): Promise<ModelResponse> {
+  const { systemPrompt, timeout: timeoutMs = 60000 } = options
+
+  const controller = new AbortController()
+  const timeoutId = setTimeout(() => controller.abort(), timeoutMs)
+
+  try {
+    const ollamaURL = `${baseURL.replace(/\/+$/, '')}/api/chat`
+    const response = await fetch(ollamaURL, {
+      method: 'POST',
+      headers: withCustomHeaders({
+        'Content-Type': 'application/json',
+        ...(apiKey && { 'Authorization': `Bearer ${apiKey}` }),
+      }, options),
       body: JSON.stringify({
         model: modelId,
-        messages: [{ role: 'user', content: prompt }],
-        system: systemPrompt,
-        max_tokens: options.maxTokens ?? 2000,
-        temperature: options.temperature ?? 0.7,
+        messages: [
+          ...(systemPrompt ? [{ role: 'system', content: systemPrompt }] : []),
+          { role: 'user', content: prompt },
+        ],
+        stream: false,
+        options: {
+          num_predict: options.maxTokens ?? FALLBACK_MAX_TOKENS,
+          ...(options.temperature !== undefined && { temperature: options.temperature }),
+          ...(options.topP !== undefined && { top_p: options.topP }),
+        },
+        ...(options.responseSchema && { format: options.responseSchema }),
       }),
       signal: controller.signal,
+      redirect: 'manual',
+      proxy: resolveProxy(ollamaURL, options.proxy),
     })
 
     clearTimeout(timeoutId)
 
+    assertNotRedirected('Ollama', response)
+
     if (!response.ok) {
       const error = await response.text()
-      throw new Error(`MiniMax API error: ${response.status} - ${error}`)
+      throw new ProviderError(
+        `Ollama API error: ${response.status} - ${error}`,
+        classifyProviderError(response.status, error),
+        response.status
+      )
+    }
+
+    type OllamaChunk = {
+      message?: { content?: string; thinking?: string }
+      done?: boolean
+      done_reason?: string
+      prompt_eval_count?: number
+      eval_count?: number
+    }
+    const chunks = (await response.text())
+      .split('\n')
+      .filter(line => line.trim())
+      .map(line => JSON.parse(line) as OllamaChunk)
+    const content = chunks.map(c => c.message?.content ?? '').join('')
+    const thinking = chunks.map(c => c.message?.thinking ?? '').join('')
+    const final = chunks.find(c => c.done) ?? chunks[chunks.length - 1]
+
+    if (!content) {
+      throw new Error('Empty response from Ollama API')
+    }
+
+    return {
+      content,
+      ...(thinking && { thinking }),
+      usage: {
+        inputTokens: final?.prompt_eval_count,
+        outputTokens: final?.eval_count,
+      },
+      finishReason: final?.done_reason,
+      raw: chunks.length === 1 ? chunks[0] : chunks,
+    }
+  } catch (error) {
+    clearTimeout(timeoutId)
+    throw error
+  }
+}
+
+/**
+ * Call Google Gemini API directly (generateContent endpoint)
+ */
+async function callGeminiAPI(
+  baseURL: string,
+  apiKey: string,
+  modelId: string,
+  prompt: string,
+  options: ModelCallOptions = {}
+): Promise<ModelResponse> {
+  const { systemPrompt, timeout: timeoutMs = 60000 } = options
+
+  const controller = new AbortController()
+  const timeoutId = setTimeout(() => controller.abort(), timeoutMs)
+
+  try {
+    const base = (baseURL || 'https://generativelanguage.googleapis.com').replace(/\/+$/, '')
+    const url = `${base}/v1beta/models/${encodeURIComponent(modelId)}:generateContent?key=${encodeURIComponent(apiKey)}`
+
+    const response = await fetch(url, {
+      method: 'POST',
+      headers: withCustomHeaders({
+        'Content-Type': 'application/json',
+      }, options),
+      body: JSON.stringify({
+        contents: [{ role: 'user', parts: [{ text: prompt }] }],
+        ...(systemPrompt && {
+          systemInstruction: { parts: [{ text: systemPrompt }] },
+        }),
+        generationConfig: {
+          maxOutputTokens: options.maxTokens ?? FALLBACK_MAX_TOKENS,
+          ...(options.temperature !== undefined && { temperature: options.temperature }),
+          ...(options.topP !== undefined && { topP: options.topP }),
+          ...(options.responseSchema && {
+            responseMimeType: 'application/json',
+            responseSchema: toGeminiSchema(options.responseSchema),
+          }),
+        },
+      }),
+      signal: controller.signal,
+      redirect: 'manual',
+      proxy: resolveProxy(url, options.proxy),
+    })
+
+    clearTimeout(timeoutId)
+
+    assertNotRedirected('Gemini', response)
+
+    if (!response.ok) {
+      const error = await response.text()
+      throw new ProviderError(
+        `Gemini API error: ${response.status} - ${error}`,
+        classifyProviderError(response.status, error),
+        response.status
+      )
     }
 
     const data = await response.json() as {
-      content?: Array<{ type: string; text?: string }>
-      usage?: { input_tokens?: number; output_tokens?: number }
-      stop_reason?: string
+      candidates?: Array<{
+        content?: { role?: string; parts?: Array<{ text?: string }> }
+        finishReason?: string
+      }>
+      usageMetadata?: { promptTokenCount?: number; candidatesTokenCount?: number; totalTokenCount?: number }
     }
-    // Find the first text content in the response (skip thinking blocks)
-    const textContent = data.content?.find(c => c.type === 'text')
-    const content = textContent?.text
+    const candidate = data.candidates?.[0]
+    const content = candidate?.content?.parts
+      ?.map(p => p.text ?? '')
+      .join('')
 
     if (!content) {
-      throw new Error('Empty response from MiniMax API')
+      throw new Error('Empty response from Gemini API')
     }
 
     return {
       content,
       usage: {
-        inputTokens: data.usage?.input_tokens,
-        outputTokens: data.usage?.output_tokens,
+        inputTokens: data.usageMetadata?.promptTokenCount,
+        outputTokens: data.usageMetadata?.candidatesTokenCount,
+        totalTokens: data.usageMetadata?.totalTokenCount,
       },
-      finishReason: data.stop_reason,
+      finishReason: candidate?.finishReason,
+      raw: data,
     }
   } catch (error) {
     clearTimeout(timeoutId)
@@ -142,6 +658,11 @@ async function callMiniMaxAPI(
   }
 }
 
+/**
+ * Characters of prompt and reply text kept in debug diagnostics
+ */
+const DIAGNOSTIC_TEXT_LENGTH = 200
+
 /**
  * Response from a model call
  */
@@ -153,6 +674,10 @@ export interface ModelResponse {
     totalTokens?: number
   }
   finishReason?: string
+  /** Full provider response body, when the call went directly to the provider API */
+  raw?: unknown
+  /** Extended thinking returned alongside the reply, if any */
+  thinking?: string
 }
 
 /**
@@ -161,14 +686,34 @@ export interface ModelResponse {
 export interface ModelCallOptions {
   /** System prompt */
   systemPrompt?: string
+  /** HTTP(S) proxy for direct API calls (set from the model's config) */
+  proxy?: string
+  /** Extra headers for direct API calls (set from the model's config) */
+  headers?: Record<string, string>
+  /** Let `headers` replace built-in headers */
+  overrideHeaders?: boolean
   /** Maximum tokens to generate */
   maxTokens?: number
-  /** Temperature for generation */
+  /** Temperature for generation (omitted from the request when unset) */
   temperature?: number
+  /** Nucleus sampling probability (omitted from the request when unset) */
+  topP?: number
   /** Timeout in milliseconds */
   timeout?: number
   /** Number of retries */
   retries?: number
+  /** Receives text deltas as they stream in (direct Anthropic-compatible calls only) */
+  onChunk?: (text: string) => void
+  /** Called as each attempt starts, so streamed deltas from a failed attempt can be discarded */
+  onAttempt?: () => void
+  /** Ask for structured output matching this schema (OpenAI-compatible, Ollama and Gemini calls) */
+  responseSchema?: JsonSchema
+  /** How OpenAI-compatible calls ask for structured output (default json_schema) */
+  responseFormat?: 'json_schema' | 'json_object'
+  /** Mark the json_schema response format strict */
+  strictSchema?: boolean
+  /** Hold the reply to the participant's response schema (set for round replies only) */
+  enforceSchema?: boolean
 }
 
 /**
@@ -188,12 +733,71 @@ export interface OpencodeClient {
       }
     }) => Promise<{
       data?: {
+        /** The assistant message, with its token counts when OpenCode reports them */
+        info?: {
+          tokens?: { input?: number; output?: number; reasoning?: number }
+        }
         parts?: Array<{ type: string; text?: string }>
       }
     }>
   }
 }
 
+/**
+ * Wait before retrying a failed call
+ *
+ * An overloaded provider gets a longer, jittered wait so the council's
+ * calls don't all retry at the same moment. Rate limits keep the normal
+ * backoff.
+ */
+function retryDelay(error: Error, delay: number): number {
+  if (error instanceof ProviderError && error.kind === 'overloaded') {
+    return delay * 2 + Math.random() * delay
+  }
+  return delay
+}
+
+/**
+ * Convert a JSON schema to Gemini's OpenAPI-style schema, which names
+ * types in upper case and marks nullable values with a flag
+ */
+function toGeminiSchema(schema: JsonSchema): Record<string, unknown> {
+  const { type, properties, items, ...rest } = schema
+  return {
+    ...rest,
+    ...(type === 'null' ? { nullable: true } : type && { type: type.toUpperCase() }),
+    ...(properties && {
+      properties: Object.fromEntries(
+        Object.entries(properties).map(([key, propSchema]) => [key, toGeminiSchema(propSchema)])
+      ),
+    }),
+    ...(items && { items: toGeminiSchema(items) }),
+  }
+}
+
+/**
+ * Describe the reply format in the system prompt
+ *
+ * Providers without a structured output mode, and requests through the
+ * OpenCode client, only learn the expected shape this way.
+ */
+function withSchemaInstruction(systemPrompt: string | undefined, schema: JsonSchema): string {
+  const instruction = `Reply with only a JSON value matching this JSON schema, without commentary or a code fence:\n${JSON.stringify(schema)}`
+  return systemPrompt ? `${systemPrompt}\n\n${instruction}` : instruction
+}
+
+/**
+ * Check a structured reply, tolerating a surrounding Markdown code fence
+ */
+function schemaError(content: string, schema: JsonSchema): string | null {
+  const json = content.trim().replace(/^```(?:json)?\s*([\s\S]*?)\s*```$/, '$1')
+  try {
+    return validateJsonSchema(JSON.parse(json), schema)
+  } catch {
+    return 'reply is not valid JSON'
+  }
+}
+
 /**
  * Provider adapter class
  */
@@ -209,6 +813,13 @@ export class ProviderAdapter {
     this.client = client
   }
 
+  /**
+   * Whether calls go through the OpenCode client rather than direct API calls
+   */
+  get hasClient(): boolean {
+    return !!this.client
+  }
+
   /**
    * Call a model with a prompt directly via API
    * Used when OpenCode client is not available (e.g., in tests)
@@ -220,6 +831,10 @@ export class ProviderAdapter {
   ): Promise<ModelResponse> {
     const { provider } = participant
     const { systemPrompt, timeout: timeoutMs = this.defaultTimeout } = options
+    const maxTokens = options.maxTokens ?? provider.maxTokens ?? defaultMaxTokens(provider.id)
+    const temperature = options.temperature ?? provider.temperature
+    const topP = options.topP ?? provider.topP
+    const responseSchema = options.enforceSchema ? provider.responseSchema : undefined
 
     const callFn = async (): Promise<ModelResponse> => {
       switch (provider.id) {
@@ -227,15 +842,90 @@ export class ProviderAdapter {
           return callKimiAPI(provider.apiKey, provider.modelId, prompt, {
             systemPrompt,
             timeout: timeoutMs,
-            temperature: options.temperature,
-            maxTokens: options.maxTokens,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            onChunk: options.onChunk,
           })
         case 'minimax':
           return callMiniMaxAPI(provider.apiKey, provider.modelId, prompt, {
             systemPrompt,
             timeout: timeoutMs,
-            temperature: options.temperature,
-            maxTokens: options.maxTokens,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            onChunk: options.onChunk,
+          })
+        case 'openai':
+          return callOpenAICompatibleAPI('OpenAI', openAIEndpoint(provider.baseURL || 'https://api.openai.com/v1', provider.apiKey), provider.modelId, prompt, {
+            systemPrompt,
+            timeout: timeoutMs,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            responseSchema,
+            strictSchema: provider.strictSchema,
+          })
+        case 'deepseek':
+          // DeepSeek has no json_schema response format; JSON mode plus the
+          // schema in the system prompt stand in for it
+          return callOpenAICompatibleAPI('DeepSeek', openAIEndpoint(provider.baseURL || 'https://api.deepseek.com', provider.apiKey), provider.modelId, prompt, {
+            systemPrompt,
+            timeout: timeoutMs,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            responseSchema,
+            responseFormat: 'json_object',
+          })
+        case 'azure':
+          return callOpenAICompatibleAPI('Azure OpenAI', azureEndpoint(provider), provider.modelId, prompt, {
+            systemPrompt,
+            timeout: timeoutMs,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            responseSchema,
+            strictSchema: provider.strictSchema,
+          })
+        case 'ollama':
+          return callOllamaAPI(provider.baseURL || 'http://localhost:11434', provider.apiKey, provider.modelId, prompt, {
+            systemPrompt,
+            timeout: timeoutMs,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            responseSchema,
+          })
+        case 'google':
+          return callGeminiAPI(provider.baseURL, provider.apiKey, provider.modelId, prompt, {
+            systemPrompt,
+            timeout: timeoutMs,
+            temperature,
+            topP,
+            maxTokens,
+            proxy: provider.proxy,
+            headers: provider.headers,
+            overrideHeaders: provider.overrideHeaders,
+            responseSchema,
           })
         default:
           throw new Error(`Direct API not supported for provider: ${provider.id}`)
@@ -247,11 +937,81 @@ export class ProviderAdapter {
 
   /**
    * Call a model with a prompt
+   *
+   * Error messages and stacks are scrubbed of the participant's API key,
+   * since providers may echo request details back in error bodies.
    */
   async call(
     participant: Participant,
     prompt: string,
     options: ModelCallOptions = {}
+  ): Promise<ModelResponse> {
+    const { apiKey } = participant.provider
+    const responseSchema = options.enforceSchema ? participant.provider.responseSchema : undefined
+    const startedAt = Date.now()
+    const callOptions = responseSchema
+      ? { ...options, systemPrompt: withSchemaInstruction(options.systemPrompt, responseSchema) }
+      : options
+    logger.debug('request', {
+      participant: participant.name,
+      model: participant.provider.modelId,
+      messages: callOptions.systemPrompt ? 2 : 1,
+      prompt: redactSecrets(truncate(prompt, DIAGNOSTIC_TEXT_LENGTH), [apiKey]),
+    })
+
+    try {
+      const response = await this.callWithRetry(participant, prompt, callOptions)
+      const checked = responseSchema
+        ? await this.checkStructuredReply(participant, prompt, callOptions, response)
+        : response
+      logger.debug('response', {
+        participant: participant.name,
+        latencyMs: Date.now() - startedAt,
+        outputTokens: checked.usage?.outputTokens,
+        text: redactSecrets(truncate(checked.content, DIAGNOSTIC_TEXT_LENGTH), [apiKey]),
+      })
+      return checked
+    } catch (error) {
+      const err = error instanceof Error ? error : new Error(String(error))
+      err.message = redactSecrets(err.message, [apiKey])
+      if (err.stack) err.stack = redactSecrets(err.stack, [apiKey])
+      logger.debug('request failed', {
+        participant: participant.name,
+        latencyMs: Date.now() - startedAt,
+        error: err.message,
+      })
+      throw err
+    }
+  }
+
+  /**
+   * Validate a reply against the participant's response schema, asking
+   * once more when it doesn't conform
+   */
+  private async checkStructuredReply(
+    participant: Participant,
+    prompt: string,
+    options: ModelCallOptions,
+    response: ModelResponse
+  ): Promise<ModelResponse> {
+    const schema = participant.provider.responseSchema!
+    if (!schemaError(response.content, schema)) return response
+
+    const retried = await this.callWithRetry(participant, prompt, options)
+    const error = schemaError(retried.content, schema)
+    if (error) {
+      throw new Error(`Reply from ${participant.name} does not match the response schema: ${error}`)
+    }
+    return retried
+  }
+
+  /**
+   * Call a model with timeout and retry applied
+   */
+  private async callWithRetry(
+    participant: Participant,
+    prompt: string,
+    options: ModelCallOptions
   ): Promise<ModelResponse> {
     const {
       systemPrompt,
@@ -263,19 +1023,31 @@ export class ProviderAdapter {
 
     const { provider } = participant
 
+    // Each attempt's timeout covers its wait for the account's rate limit;
+    // a timed-out attempt leaves the queue instead of holding its place
+    const withTimeout = (call: () => Promise<ModelResponse>) => () => {
+      const waiting = new AbortController()
+      const attempt = acquireRequestSlot(rateLimitKey(provider), waiting.signal).then(() => {
+        options.onAttempt?.()
+        return call()
+      })
+      return timeout(attempt, timeoutMs, t('errors.timeout', { participant: participant.name }))
+        .catch(error => {
+          waiting.abort()
+          throw toProviderError(error)
+        })
+    }
+
     // If no OpenCode client is set, fall back to direct API calls
     if (!this.client) {
-      const callWithTimeout = () =>
-        timeout(
-          this.callDirectAPI(participant, prompt, options),
-          timeoutMs,
-          t('errors.timeout', { participant: participant.name })
-        )
+      const callWithTimeout = withTimeout(() => this.callDirectAPI(participant, prompt, options))
 
       return retry(callWithTimeout, {
         maxRetries: retries,
         initialDelay: 1000,
         backoffFactor: 2,
+        delayFor: retryDelay,
+        shouldRetry: isRetryable,
       })
     }
 
@@ -302,16 +1074,25 @@ export class ProviderAdapter {
         throw new Error(t('errors.apiError', { message: 'Empty response' }))
       }
 
-      return { content }
+      // Reasoning tokens are billed as output
+      const tokens = response.data?.info?.tokens
+      return {
+        content,
+        ...(tokens?.input !== undefined && tokens.output !== undefined && {
+          usage: { inputTokens: tokens.input, outputTokens: tokens.output + (tokens.reasoning ?? 0) },
+        }),
+      }
     }
 
     // Apply timeout and retry
-    const callWithTimeout = () => timeout(callFn(), timeoutMs, t('errors.timeout', { participant: participant.name }))
+    const callWithTimeout = withTimeout(callFn)
 
     return retry(callWithTimeout, {
       maxRetries: retries,
       initialDelay: 1000,
       backoffFactor: 2,
+      delayFor: retryDelay,
+      shouldRetry: isRetryable,
     })
   }
 
@@ -438,7 +1219,49 @@ export const PREDEFINED_PROVIDERS = {
       apiKey,
       modelId
     ),
+
+  /**
+   * Create Google Gemini provider config
+   */
+  google: (apiKey: string, modelId = 'gemini-2.5-pro'): ProviderConfig =>
+    createProviderConfig(
+      'google',
+      'Gemini',
+      'https://generativelanguage.googleapis.com',
+      apiKey,
+      modelId
+    ),
+
+  /**
+   * Create DeepSeek provider config (OpenAI-compatible API)
+   */
+  deepseek: (apiKey: string, modelId = 'deepseek-chat'): ProviderConfig =>
+    createProviderConfig(
+      'deepseek',
+      'DeepSeek',
+      'https://api.deepseek.com',
+      apiKey,
+      modelId
+    ),
+
+  /**
+   * Create Ollama provider config for a local model (no API key needed)
+   */
+  ollama: (apiKey = '', modelId = 'llama3.2'): ProviderConfig =>
+    createProviderConfig(
+      'ollama',
+      'Ollama',
+      'http://localhost:11434',
+      apiKey,
+      modelId
+    ),
 }
 
 // Singleton instance
 export const providerAdapter = new ProviderAdapter()
+
+/**
+ * The part of a provider adapter the council calls, so tests can supply
+ * their own in place of the shared instance
+ */
+export type ModelCaller = Pick<ProviderAdapter, 'setClient' | 'call'>
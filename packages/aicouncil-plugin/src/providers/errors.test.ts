@@ -0,0 +1,77 @@
+import { describe, it, expect } from 'vitest'
+import { ProviderError, classifyProviderError, toProviderError, isRetryable } from './errors'
+import { TimeoutError } from '../utils'
+
+describe('classifyProviderError', () => {
+  it('should tell an Anthropic overload from a rate limit', () => {
+    const overloaded = '{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}'
+    const rateLimited = '{"type":"error","error":{"type":"rate_limit_error","message":"Too many requests"}}'
+
+    expect(classifyProviderError(529, overloaded)).toBe('overloaded')
+    expect(classifyProviderError(429, rateLimited)).toBe('rate_limit')
+  })
+
+  it('should prefer the error type over the status code', () => {
+    expect(classifyProviderError(500, '{"error":{"type":"overloaded_error"}}')).toBe('overloaded')
+  })
+
+  it('should recognise OpenAI and Google error shapes', () => {
+    expect(classifyProviderError(429, '{"error":{"code":"rate_limit_exceeded"}}')).toBe('rate_limit')
+    expect(classifyProviderError(503, '{"error":{"status":"UNAVAILABLE"}}')).toBe('overloaded')
+  })
+
+  it('should fall back to the status code for non-JSON bodies', () => {
+    expect(classifyProviderError(529, 'Overloaded')).toBe('overloaded')
+    expect(classifyProviderError(429, 'Too Many Requests')).toBe('rate_limit')
+    expect(classifyProviderError(500, 'Internal Server Error')).toBe('server')
+    expect(classifyProviderError(400, 'Bad Request')).toBe('unknown')
+  })
+
+  it('should recognise rejected keys', () => {
+    expect(classifyProviderError(401, '{"type":"error","error":{"type":"authentication_error"}}')).toBe('auth')
+    expect(classifyProviderError(400, '{"error":{"code":"invalid_api_key"}}')).toBe('auth')
+    expect(classifyProviderError(403, 'Forbidden')).toBe('auth')
+  })
+
+  it('should accept a bare error type', () => {
+    expect(classifyProviderError(undefined, 'overloaded_error')).toBe('overloaded')
+  })
+})
+
+describe('ProviderError', () => {
+  it('should carry the kind and status', () => {
+    const error = new ProviderError('Kimi API error: 529 - Overloaded', 'overloaded', 529)
+
+    expect(error).toBeInstanceOf(Error)
+    expect(error.name).toBe('ProviderError')
+    expect(error.kind).toBe('overloaded')
+    expect(error.status).toBe(529)
+  })
+})
+
+describe('toProviderError', () => {
+  it('should classify timeouts and unreachable hosts', () => {
+    const timedOut = toProviderError(new TimeoutError('Kimi timed out'))
+    const unreachable = toProviderError(new TypeError('fetch failed'))
+
+    expect(timedOut).toMatchObject({ kind: 'timeout', message: 'Kimi timed out' })
+    expect(unreachable).toMatchObject({ kind: 'network', message: 'fetch failed' })
+    expect((unreachable as Error).cause).toBeInstanceOf(TypeError)
+  })
+
+  it('should leave other errors alone', () => {
+    const providerError = new ProviderError('Overloaded', 'overloaded', 529)
+    const other = new Error('Empty response')
+
+    expect(toProviderError(providerError)).toBe(providerError)
+    expect(toProviderError(other)).toBe(other)
+  })
+})
+
+describe('isRetryable', () => {
+  it('should not retry rejected keys', () => {
+    expect(isRetryable(new ProviderError('Unauthorized', 'auth', 401))).toBe(false)
+    expect(isRetryable(new ProviderError('Too many requests', 'rate_limit', 429))).toBe(true)
+    expect(isRetryable(new Error('Empty response'))).toBe(true)
+  })
+})
@@ -0,0 +1,116 @@
+/**
+ * Provider Errors
+ *
+ * Classifies provider API failures so retry policies can react to them
+ */
+
+import { TimeoutError } from '../utils'
+
+/**
+ * Kind of provider failure
+ *
+ * - auth: the API key is missing, invalid or lacks access (401/403)
+ * - overloaded: the provider is temporarily out of capacity (Anthropic 529)
+ * - rate_limit: this key sent too many requests (429)
+ * - server: the provider failed on its side (5xx)
+ * - timeout: no response within the time allowed
+ * - network: the provider could not be reached at all
+ */
+export type ProviderErrorKind =
+  | 'auth'
+  | 'overloaded'
+  | 'rate_limit'
+  | 'server'
+  | 'timeout'
+  | 'network'
+  | 'unknown'
+
+/**
+ * Error raised for a failed provider API call
+ */
+export class ProviderError extends Error {
+  readonly kind: ProviderErrorKind
+  readonly status?: number
+
+  constructor(message: string, kind: ProviderErrorKind, status?: number, options?: ErrorOptions) {
+    super(message, options)
+    this.name = 'ProviderError'
+    this.kind = kind
+    this.status = status
+  }
+}
+
+/**
+ * Read the provider's error type from an error body, if it has one
+ *
+ * Handles Anthropic (`error.type`), OpenAI (`error.type` / `error.code`)
+ * and Google (`error.status`) shapes.
+ */
+function errorType(body: string): string | undefined {
+  try {
+    const parsed = JSON.parse(body) as {
+      error?: { type?: string; code?: string; status?: string }
+    }
+    return parsed.error?.type ?? parsed.error?.code ?? parsed.error?.status
+  } catch {
+    return undefined
+  }
+}
+
+/**
+ * Classify a provider failure from its error type, falling back to the status
+ */
+export function classifyProviderError(status: number | undefined, typeOrBody?: string): ProviderErrorKind {
+  const type = typeOrBody?.trimStart().startsWith('{') ? errorType(typeOrBody) : typeOrBody
+
+  switch (type) {
+    case 'authentication_error':
+    case 'permission_error':
+    case 'invalid_api_key':
+    case 'UNAUTHENTICATED':
+    case 'PERMISSION_DENIED':
+      return 'auth'
+    case 'overloaded_error':
+    case 'UNAVAILABLE':
+      return 'overloaded'
+    case 'rate_limit_error':
+    case 'rate_limit_exceeded':
+    case 'RESOURCE_EXHAUSTED':
+      return 'rate_limit'
+  }
+
+  if (status === 401 || status === 403) return 'auth'
+  if (status === 529) return 'overloaded'
+  if (status === 429) return 'rate_limit'
+  if (status === 408) return 'timeout'
+  if (status !== undefined && status >= 500) return 'server'
+  return 'unknown'
+}
+
+/**
+ * Turn a timeout or connection failure into a ProviderError
+ *
+ * Provider errors and anything else unrecognised are returned unchanged.
+ */
+export function toProviderError(error: unknown): unknown {
+  if (error instanceof ProviderError) {
+    return error
+  }
+  if (error instanceof TimeoutError || (error instanceof Error && error.name === 'AbortError')) {
+    return new ProviderError(error.message, 'timeout', undefined, { cause: error })
+  }
+  // fetch rejects with a TypeError when the host can't be reached
+  if (error instanceof TypeError && /fetch failed|network|ECONNREFUSED|ENOTFOUND/i.test(error.message)) {
+    return new ProviderError(error.message, 'network', undefined, { cause: error })
+  }
+  return error
+}
+
+/**
+ * Whether a failed call is worth retrying
+ *
+ * A bad or unauthorized key fails the same way every time.
+ */
+export function isRetryable(error: Error): boolean {
+  return !(error instanceof ProviderError && error.kind === 'auth')
+}
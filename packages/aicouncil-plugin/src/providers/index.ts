@@ -8,5 +8,19 @@ export {
   createProviderConfig,
   PREDEFINED_PROVIDERS,
   type ModelCallOptions,
+  type ModelCaller,
   type OpencodeClient,
 } from './adapter'
+export {
+  ProviderError,
+  classifyProviderError,
+  toProviderError,
+  isRetryable,
+  type ProviderErrorKind,
+} from './errors'
+export {
+  setRequestsPerMinute,
+  getRequestsPerMinute,
+  rateLimitKey,
+  acquireRequestSlot,
+} from './rate-limit'
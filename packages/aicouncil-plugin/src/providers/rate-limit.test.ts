@@ -0,0 +1,83 @@
+import { describe, it, expect, beforeEach, afterEach, vi } from 'vitest'
+import { setRequestsPerMinute, getRequestsPerMinute, rateLimitKey, acquireRequestSlot } from './rate-limit'
+import type { ProviderConfig } from '../types'
+
+describe('rateLimitKey', () => {
+  const provider: ProviderConfig = { id: 'openai', name: 'GPT', modelId: 'gpt-4o', apiKey: 'key-1' }
+
+  it('should share a key across models on the same account', () => {
+    expect(rateLimitKey({ ...provider, modelId: 'gpt-4o-mini' })).toBe(rateLimitKey(provider))
+  })
+
+  it('should separate different keys and endpoints', () => {
+    expect(rateLimitKey({ ...provider, apiKey: 'key-2' })).not.toBe(rateLimitKey(provider))
+    expect(rateLimitKey({ ...provider, baseURL: 'https://proxy.example' })).not.toBe(rateLimitKey(provider))
+  })
+})
+
+describe('acquireRequestSlot', () => {
+  beforeEach(() => {
+    vi.useFakeTimers()
+  })
+
+  afterEach(() => {
+    setRequestsPerMinute(0)
+    vi.useRealTimers()
+  })
+
+  it('should not wait when no limit is set', async () => {
+    setRequestsPerMinute(0)
+    await expect(acquireRequestSlot('a')).resolves.toBeUndefined()
+    expect(getRequestsPerMinute()).toBe(0)
+  })
+
+  it('should hold requests over the budget until a token refills', async () => {
+    setRequestsPerMinute(2)
+    await acquireRequestSlot('a')
+    await acquireRequestSlot('a')
+
+    let released = false
+    const third = acquireRequestSlot('a').then(() => { released = true })
+    await vi.advanceTimersByTimeAsync(29000)
+    expect(released).toBe(false)
+
+    await vi.advanceTimersByTimeAsync(1000)
+    await third
+    expect(released).toBe(true)
+  })
+
+  it('should drop an aborted waiter from the queue without using a token', async () => {
+    setRequestsPerMinute(1)
+    await acquireRequestSlot('a')
+
+    const controller = new AbortController()
+    const aborted = acquireRequestSlot('a', controller.signal)
+    let released = false
+    const next = acquireRequestSlot('a').then(() => { released = true })
+
+    controller.abort()
+    await expect(aborted).rejects.toThrow('aborted')
+
+    await vi.advanceTimersByTimeAsync(60000)
+    await next
+    expect(released).toBe(true)
+  })
+
+  it('should reject at once when the signal has already aborted', async () => {
+    setRequestsPerMinute(1)
+    const controller = new AbortController()
+    controller.abort()
+
+    await expect(acquireRequestSlot('a', controller.signal)).rejects.toThrow('aborted')
+  })
+
+  it('should keep separate budgets per key', async () => {
+    setRequestsPerMinute(1)
+    await acquireRequestSlot('a')
+
+    let released = false
+    acquireRequestSlot('b').then(() => { released = true })
+    await vi.advanceTimersByTimeAsync(0)
+    expect(released).toBe(true)
+  })
+})
@@ -0,0 +1,110 @@
+/**
+ * Provider Rate Limiting
+ *
+ * Token buckets shared by every model on the same provider account, so a
+ * council with several models behind one key stays under the account's
+ * request limit
+ */
+
+import type { ProviderConfig } from '../types'
+
+interface Bucket {
+  tokens: number
+  updatedAt: number
+  /** Tail of the queue of callers waiting for a token */
+  queue: Promise<void>
+}
+
+let requestsPerMinute = 0
+const buckets = new Map<string, Bucket>()
+
+/**
+ * Set the request budget per provider account (0 disables the limit)
+ *
+ * Existing buckets are dropped, so the new limit starts with a full bucket.
+ */
+export function setRequestsPerMinute(limit: number): void {
+  requestsPerMinute = Math.max(0, limit)
+  buckets.clear()
+}
+
+/**
+ * Get the current request budget per provider account
+ */
+export function getRequestsPerMinute(): number {
+  return requestsPerMinute
+}
+
+/**
+ * Key a provider config to the account its requests count against
+ */
+export function rateLimitKey(provider: ProviderConfig): string {
+  return [provider.id, provider.baseURL ?? '', provider.apiKey ?? ''].join('|')
+}
+
+/**
+ * Sleep for `ms`, waking early if the signal aborts
+ */
+function wait(ms: number, signal?: AbortSignal): Promise<void> {
+  return new Promise(resolve => {
+    const done = () => {
+      clearTimeout(timer)
+      signal?.removeEventListener('abort', done)
+      resolve()
+    }
+    const timer = setTimeout(done, ms)
+    signal?.addEventListener('abort', done, { once: true })
+  })
+}
+
+/**
+ * Error for a wait given up through its abort signal
+ */
+function abortError(signal: AbortSignal): Error {
+  return signal.reason instanceof Error ? signal.reason : new Error('Request slot wait aborted')
+}
+
+/**
+ * Wait until the account behind `key` may send another request
+ *
+ * Callers are served in order. Resolves at once when no limit is set.
+ * When `signal` aborts, the wait rejects and the caller leaves the queue
+ * without using a token.
+ */
+export function acquireRequestSlot(key: string, signal?: AbortSignal): Promise<void> {
+  const limit = requestsPerMinute
+  if (limit <= 0) return Promise.resolve()
+  if (signal?.aborted) return Promise.reject(abortError(signal))
+
+  let bucket = buckets.get(key)
+  if (!bucket) {
+    bucket = { tokens: limit, updatedAt: Date.now(), queue: Promise.resolve() }
+    buckets.set(key, bucket)
+  }
+
+  const current = bucket
+  const turn = current.queue.then(async () => {
+    for (;;) {
+      if (signal?.aborted) return
+      const now = Date.now()
+      current.tokens = Math.min(limit, current.tokens + (now - current.updatedAt) * limit / 60000)
+      current.updatedAt = now
+      if (current.tokens >= 1) {
+        current.tokens -= 1
+        return
+      }
+      await wait(Math.ceil((1 - current.tokens) * 60000 / limit), signal)
+    }
+  })
+  current.queue = turn
+  if (!signal) return turn
+
+  return new Promise((resolve, reject) => {
+    const onAbort = () => reject(abortError(signal))
+    signal.addEventListener('abort', onAbort, { once: true })
+    turn.then(() => {
+      signal.removeEventListener('abort', onAbort)
+      resolve()
+    })
+  })
+}
@@ -0,0 +1,95 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
+import { executeAdd, addInputSchema } from './add'
+import { getCouncil } from '../core/council'
+import { providerAdapter } from '../providers/adapter'
+
+vi.mock('../core/council', async () => {
+  const actual = await vi.importActual('../core/council')
+  return {
+    ...actual,
+    getCouncil: vi.fn(),
+  }
+})
+
+describe('addInputSchema', () => {
+  it('should take one model\'s settings', () => {
+    expect(addInputSchema.safeParse({ providerId: 'openai', name: 'Reviewer' }).success).toBe(true)
+    expect(addInputSchema.safeParse({ name: 'Reviewer' }).success).toBe(false)
+  })
+})
+
+describe('executeAdd', () => {
+  const mockCouncil = {
+    participants: [
+      { id: 'p1', name: 'Host', isHost: true },
+      { id: 'p2', name: 'Participant', isHost: false },
+    ],
+    addParticipant: vi.fn(),
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    vi.mocked(getCouncil).mockReturnValue(mockCouncil as any)
+    mockCouncil.addParticipant.mockImplementation((provider, options) => ({
+      id: 'p3',
+      name: options?.name ?? provider.name,
+      isHost: false,
+    }))
+  })
+
+  afterEach(() => {
+    vi.restoreAllMocks()
+  })
+
+  it('should add the model with its resolved provider config', async () => {
+    const result = await executeAdd({ providerId: 'openai', apiKey: 'sk-test', name: 'Reviewer', temperature: 0.2 })
+
+    expect(result.success).toBe(true)
+    expect(result.message).toBe('Reviewer joined the discussion')
+    expect(result.participant).toEqual({ id: 'p3', name: 'Reviewer', isHost: false })
+
+    const [provider, options] = mockCouncil.addParticipant.mock.calls[0]
+    expect(provider).toMatchObject({ id: 'openai', apiKey: 'sk-test', temperature: 0.2 })
+    expect(options).toEqual({ name: 'Reviewer', firstTurnPrompt: undefined })
+  })
+
+  it('should warn about headers the OpenCode client will ignore', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+
+    const result = await executeAdd({ providerId: 'openai', name: 'Reviewer', headers: { 'X-Team': 'platform' } })
+
+    expect(result.success).toBe(true)
+    expect(result.warnings).toEqual([
+      'headers for Reviewer only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+  })
+
+  it('should use the configured API key when none is given', async () => {
+    await executeAdd({ providerId: 'openai' }, { getApiKey: () => 'from-config' })
+
+    expect(mockCouncil.addParticipant.mock.calls[0][0].apiKey).toBe('from-config')
+  })
+
+  it('should refuse a name already in the council', async () => {
+    const result = await executeAdd({ providerId: 'openai', name: 'participant' })
+
+    expect(result.success).toBe(false)
+    expect(mockCouncil.addParticipant).not.toHaveBeenCalled()
+  })
+
+  it('should reject invalid settings', async () => {
+    const result = await executeAdd({ providerId: 'openai', temperature: 5 })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('temperature')
+  })
+
+  it('should require a council to be set up', async () => {
+    vi.mocked(getCouncil).mockReturnValue({ ...mockCouncil, participants: [] } as any)
+
+    const result = await executeAdd({ providerId: 'openai' })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('No active discussion')
+  })
+})
@@ -0,0 +1,103 @@
+/**
+ * Council Add Tool
+ *
+ * Tool for bringing a new model into the council, including mid-discussion
+ */
+
+import { getCouncil } from '../core/council'
+import { t } from '../i18n'
+import {
+  modelInputSchema,
+  validateModelInput,
+  buildProviderConfig,
+  clientIgnoredWarnings,
+  type ModelInput,
+  type SetupContext,
+} from './setup'
+
+/**
+ * Add tool input schema: one model's settings, as in council_setup.
+ * The host is chosen at setup, so it can't be set here.
+ */
+export const addInputSchema = modelInputSchema.omit({ isHost: true })
+
+export type AddInput = Omit<ModelInput, 'isHost'>
+
+/**
+ * Add tool output
+ */
+export interface AddOutput {
+  success: boolean
+  message: string
+  participant?: {
+    id: string
+    name: string
+    isHost: boolean
+  }
+  /** Settings that were accepted but will have no effect */
+  warnings?: string[]
+}
+
+/**
+ * Execute the add tool
+ *
+ * A model added while a discussion is running is primed with the
+ * discussion so far before its first turn, and its arrival is announced.
+ */
+export async function executeAdd(input: AddInput, context: SetupContext = {}): Promise<AddOutput> {
+  const council = getCouncil()
+
+  if (council.participants.length === 0) {
+    return {
+      success: false,
+      message: t('errors.noActiveDiscussion'),
+    }
+  }
+
+  const invalid = validateModelInput(input)
+  if (invalid) {
+    return {
+      success: false,
+      message: t('errors.invalidConfig', { message: invalid }),
+    }
+  }
+
+  const providerConfig = buildProviderConfig(input, context)
+  const name = input.name ?? providerConfig.name
+  if (council.participants.some(p => p.name.toLowerCase() === name.toLowerCase())) {
+    return {
+      success: false,
+      message: t('errors.duplicateParticipant', { name }),
+    }
+  }
+
+  const participant = council.addParticipant(providerConfig, {
+    name: input.name,
+    firstTurnPrompt: input.firstTurnPrompt,
+  })
+
+  const warnings = clientIgnoredWarnings(input)
+
+  return {
+    success: true,
+    message: t('participant.joined', { name: participant.name }),
+    participant: {
+      id: participant.id,
+      name: participant.name,
+      isHost: participant.isHost,
+    },
+    ...(warnings.length > 0 && { warnings }),
+  }
+}
+
+/**
+ * Create the add tool definition for OpenCode plugin
+ */
+export function createAddTool() {
+  return {
+    name: 'council_add',
+    description: t('commands.add.description'),
+    parameters: addInputSchema,
+    execute: executeAdd,
+  }
+}
@@ -53,6 +53,7 @@ describe('executeDiscuss', () => {
     currentRound: 0,
     startDiscussion: vi.fn(),
     nextRound: vi.fn(),
+    previewDiscussion: vi.fn(),
     on: vi.fn().mockReturnValue(unsubscribeMock),
   }
 
@@ -121,6 +122,23 @@ describe('executeDiscuss', () => {
     })
   })
 
+  it('should include thinking only when showThinking is enabled', async () => {
+    const emit = (event: string, handler: (message: unknown) => void) => {
+      if (event === 'message:new') {
+        handler({ from: 'Host', content: 'Reply', type: 'assistant', metadata: { thinking: 'Reasoning' } })
+      }
+      return unsubscribeMock
+    }
+    vi.mocked(mockCouncil.on).mockImplementation(emit as any)
+
+    const hidden = await executeDiscuss({ topic: 'Test' })
+    expect(hidden.responses[0]).not.toHaveProperty('thinking')
+
+    vi.mocked(getCouncil).mockReturnValue({ ...mockCouncil, showThinking: true } as any)
+    const shown = await executeDiscuss({ topic: 'Test' })
+    expect(shown.responses[0].thinking).toBe('Reasoning')
+  })
+
   it('should handle errors', async () => {
     vi.mocked(mockCouncil.startDiscussion).mockRejectedValue(new Error('Test error'))
 
@@ -154,4 +172,20 @@ describe('executeDiscuss', () => {
     expect(result.responses).toHaveLength(1)
     expect(result.responses[0].participant).toBe('Host')
   })
+
+  it('should preview the first round on a dry run without starting it', async () => {
+    const preview = [
+      { participantId: 'p1', name: 'Host', isHost: true, intro: false, systemPrompt: 'Lead', prompt: 'Open' },
+      { participantId: 'p2', name: 'Participant', isHost: false, intro: false, systemPrompt: 'Join', prompt: 'Reply' },
+    ]
+    mockCouncil.previewDiscussion.mockReturnValue(preview)
+
+    const result = await executeDiscuss({ topic: 'Test', dryRun: true })
+
+    expect(result.success).toBe(true)
+    expect(result.preview).toEqual(preview)
+    expect(result.message).toContain('2 requests')
+    expect(mockCouncil.previewDiscussion).toHaveBeenCalledWith('Test')
+    expect(mockCouncil.startDiscussion).not.toHaveBeenCalled()
+  })
 })
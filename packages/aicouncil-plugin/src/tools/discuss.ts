@@ -7,7 +7,7 @@
 import { z } from 'zod'
 import { getCouncil } from '../core/council'
 import { t } from '../i18n'
-import type { Message } from '../types'
+import type { Message, RequestPreview } from '../types'
 
 /**
  * Discuss tool input schema
@@ -15,11 +15,13 @@ import type { Message } from '../types'
 export const discussInputSchema = z.object({
   topic: z.string().describe('The topic or question to discuss'),
   continueDiscussion: z.boolean().optional().default(false).describe('Whether to continue an existing discussion'),
+  dryRun: z.boolean().optional().default(false).describe('Show who would speak and the prompts they would get, without calling any model'),
 })
 
 export type DiscussInput = {
   topic: string
   continueDiscussion?: boolean
+  dryRun?: boolean
 }
 
 /**
@@ -33,8 +35,12 @@ export interface DiscussOutput {
     participant: string
     content: string
     isHost: boolean
+    /** Extended thinking, only when the council has showThinking enabled */
+    thinking?: string
   }>
   isComplete: boolean
+  /** The requests the first round would send, when `dryRun` was set */
+  preview?: RequestPreview[]
 }
 
 /**
@@ -54,6 +60,28 @@ export async function executeDiscuss(input: DiscussInput): Promise<DiscussOutput
     }
   }
 
+  if (input.dryRun) {
+    try {
+      const preview = council.previewDiscussion(input.topic)
+      return {
+        success: true,
+        message: t('messages.dryRun', { count: preview.length }),
+        round: council.currentRound,
+        responses: [],
+        isComplete: false,
+        preview,
+      }
+    } catch (error) {
+      return {
+        success: false,
+        message: error instanceof Error ? error.message : String(error),
+        round: council.currentRound,
+        responses: [],
+        isComplete: false,
+      }
+    }
+  }
+
   const responses: DiscussOutput['responses'] = []
 
   // Set up callback to collect responses
@@ -64,6 +92,9 @@ export async function executeDiscuss(input: DiscussInput): Promise<DiscussOutput
         participant: message.from,
         content: message.content,
         isHost: participant.isHost,
+        ...(council.showThinking && typeof message.metadata?.thinking === 'string' && {
+          thinking: message.metadata.thinking,
+        }),
       })
     }
   })
@@ -2,6 +2,10 @@ import { describe, it, expect, vi, beforeEach } from 'vitest'
 import { executeEnd, endInputSchema } from './end'
 import { getCouncil } from '../core/council'
 
+vi.mock('node:fs/promises', () => ({
+  writeFile: vi.fn().mockResolvedValue(undefined),
+}))
+
 vi.mock('../core/council', async () => {
   const actual = await vi.importActual('../core/council')
   return {
@@ -20,12 +24,13 @@ describe('endInputSchema', () => {
   it('should use default values', () => {
     const input = {}
     const result = endInputSchema.parse(input)
-    expect(result.generateSummary).toBe(true)
+    expect(result.generateSummary).toBe(false)
   })
 })
 
 describe('executeEnd', () => {
   const mockState = {
+    topic: 'Caching',
     rounds: [
       { messages: [{}, {}] },
       { messages: [{}] },
@@ -37,10 +42,18 @@ describe('executeEnd', () => {
     discussionStatus: 'running',
     getState: vi.fn().mockReturnValue(mockState),
     endDiscussion: vi.fn().mockResolvedValue(undefined),
+    summarize: vi.fn(),
+    summary: null,
+    getUsage: vi.fn().mockReturnValue({
+      participants: [],
+      total: { inputTokens: 1200, outputTokens: 300, calls: 4 },
+    }),
   }
 
   beforeEach(() => {
     vi.clearAllMocks()
+    mockCouncil.getState.mockReturnValue(mockState)
+    mockCouncil.summarize.mockResolvedValue({ content: 'Consensus: use a write-through cache.' })
     vi.mocked(getCouncil).mockReturnValue(mockCouncil as any)
   })
 
@@ -64,14 +77,61 @@ describe('executeEnd', () => {
     expect(result.success).toBe(true)
     expect(result.totalRounds).toBe(2)
     expect(result.totalMessages).toBe(3)
+    expect(result.usage?.total).toEqual({ inputTokens: 1200, outputTokens: 300, calls: 4 })
+  })
+
+  it('should omit usage when the models did not report token counts', async () => {
+    mockCouncil.getUsage.mockReturnValueOnce(undefined)
+
+    const result = await executeEnd({})
+
+    expect(result.success).toBe(true)
+    expect(result).not.toHaveProperty('usage')
   })
 
   it('should generate summary when requested', async () => {
     const result = await executeEnd({ generateSummary: true })
 
-    expect(result.summary).toBeDefined()
-    expect(result.summary).toContain('2 rounds')
-    expect(result.summary).toContain('3 messages')
+    expect(mockCouncil.summarize).toHaveBeenCalled()
+    expect(result.summary).toBe('Consensus: use a write-through cache.')
+  })
+
+  it('should write the summary to the summary path', async () => {
+    const { writeFile } = await import('node:fs/promises')
+
+    const result = await executeEnd({ generateSummary: true, summaryPath: '/tmp/summary.md' })
+
+    expect(writeFile).toHaveBeenCalledWith(
+      '/tmp/summary.md',
+      '# Caching\n\nConsensus: use a write-through cache.\n',
+      'utf-8'
+    )
+    expect(result.message).toContain('Summary written to /tmp/summary.md')
+  })
+
+  it('should end the discussion and warn when the summary fails', async () => {
+    mockCouncil.summarize.mockRejectedValue(new Error('overloaded'))
+
+    const result = await executeEnd({ generateSummary: true })
+
+    expect(result.success).toBe(true)
+    expect(mockCouncil.endDiscussion).toHaveBeenCalled()
+    expect(result.summary).toBeUndefined()
+    expect(result.warnings).toEqual(['Could not generate the final summary: overloaded'])
+  })
+
+  it('should return the summary when it cannot be written', async () => {
+    const { writeFile } = await import('node:fs/promises')
+    vi.mocked(writeFile).mockRejectedValueOnce(new Error('ENOENT: no such file or directory'))
+
+    const result = await executeEnd({ generateSummary: true, summaryPath: '/missing/summary.md' })
+
+    expect(result.success).toBe(true)
+    expect(result.summary).toBe('Consensus: use a write-through cache.')
+    expect(result.message).not.toContain('Summary written')
+    expect(result.warnings).toEqual([
+      'Could not write the summary to /missing/summary.md: ENOENT: no such file or directory',
+    ])
   })
 
   it('should not generate summary when no messages', async () => {
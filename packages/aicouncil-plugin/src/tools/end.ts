@@ -4,19 +4,23 @@
  * Tool for ending the current discussion
  */
 
+import { writeFile } from 'node:fs/promises'
 import { z } from 'zod'
 import { getCouncil } from '../core/council'
 import { t } from '../i18n'
+import type { UsageReport } from '../types'
 
 /**
  * End tool input schema
  */
 export const endInputSchema = z.object({
-  generateSummary: z.boolean().optional().default(true).describe('Whether to generate a final summary'),
+  generateSummary: z.boolean().optional().default(false).describe('Whether to generate a final summary (one extra model call)'),
+  summaryPath: z.string().optional().describe('File to write the summary to, e.g. summary.md'),
 })
 
 export type EndInput = {
   generateSummary?: boolean
+  summaryPath?: string
 }
 
 /**
@@ -28,6 +32,10 @@ export interface EndOutput {
   totalRounds: number
   totalMessages: number
   summary?: string
+  /** Tokens used per model, with an estimated cost where pricing is set; omitted when the models didn't report token counts */
+  usage?: UsageReport
+  /** Problems that didn't stop the discussion from ending */
+  warnings?: string[]
 }
 
 /**
@@ -48,21 +56,44 @@ export async function executeEnd(input: EndInput): Promise<EndOutput> {
   const state = council.getState()
   const totalMessages = state.rounds.reduce((sum, r) => sum + r.messages.length, 0)
 
-  await council.endDiscussion()
-
-  // TODO: Generate summary if requested
+  // Summarize while the discussion is still open; a failed summary is
+  // reported but doesn't keep the discussion from ending
+  const warnings: string[] = []
   let summary: string | undefined
   if (input.generateSummary && totalMessages > 0) {
-    // For now, just create a simple summary
-    summary = `Discussion completed after ${state.rounds.length} rounds with ${totalMessages} messages.`
+    try {
+      summary = (council.summary ?? await council.summarize())?.content
+    } catch (error) {
+      warnings.push(t('errors.summaryFailed', { message: error instanceof Error ? error.message : String(error) }))
+    }
+  }
+
+  await council.endDiscussion()
+
+  let written = false
+  if (summary && input.summaryPath) {
+    try {
+      await writeFile(input.summaryPath, `# ${state.topic}\n\n${summary}\n`, 'utf-8')
+      written = true
+    } catch (error) {
+      warnings.push(t('errors.summaryWriteFailed', {
+        path: input.summaryPath,
+        message: error instanceof Error ? error.message : String(error),
+      }))
+    }
   }
 
+  const usage = council.getUsage()
   return {
     success: true,
-    message: t('discussion.completed'),
+    message: written
+      ? `${t('discussion.completed')}. ${t('messages.summaryWritten', { path: input.summaryPath! })}`
+      : t('discussion.completed'),
     totalRounds: state.rounds.length,
     totalMessages,
     summary,
+    ...(usage && { usage }),
+    ...(warnings.length > 0 && { warnings }),
   }
 }
 
@@ -0,0 +1,113 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { executeExport, exportInputSchema, renderTranscript } from './export'
+import { getCouncil } from '../core/council'
+import type { Message } from '../types'
+
+vi.mock('../core/council', async () => {
+  const actual = await vi.importActual('../core/council')
+  return {
+    ...actual,
+    getCouncil: vi.fn(),
+  }
+})
+
+vi.mock('node:fs/promises', () => ({
+  writeFile: vi.fn().mockResolvedValue(undefined),
+}))
+
+const messages: Message[] = [
+  {
+    id: 'm2',
+    from: 'Participant',
+    content: 'I agree with @Host on *most* points',
+    round: 1,
+    timestamp: new Date('2024-01-15T10:01:00Z'),
+    type: 'assistant',
+  },
+  {
+    id: 'm1',
+    from: 'Host',
+    content: 'Let us discuss <caching>',
+    round: 1,
+    timestamp: new Date('2024-01-15T10:00:00Z'),
+    type: 'assistant',
+  },
+]
+
+describe('exportInputSchema', () => {
+  it('should default to markdown', () => {
+    const result = exportInputSchema.parse({})
+    expect(result.format).toBe('markdown')
+  })
+
+  it('should reject unknown formats', () => {
+    const result = exportInputSchema.safeParse({ format: 'pdf' })
+    expect(result.success).toBe(false)
+  })
+})
+
+describe('renderTranscript', () => {
+  it('should render markdown ordered by timestamp with escaped content', () => {
+    const markdown = renderTranscript('Caching', messages, 'markdown')
+
+    expect(markdown).toBe(
+      '# Caching\n\n' +
+      '### [Host] (2024-01-15 10:00:00)\n\nLet us discuss \\<caching\\>\n\n' +
+      '### [Participant] (2024-01-15 10:01:00)\n\nI agree with **@Host** on \\*most\\* points\n'
+    )
+  })
+
+  it('should escape HTML and bold mentions', () => {
+    const html = renderTranscript('Caching', messages, 'html')
+
+    expect(html).toContain('<h3>[Host] (2024-01-15 10:00:00)</h3>')
+    expect(html).toContain('<p>Let us discuss &lt;caching&gt;</p>')
+    expect(html).toContain('<strong>@Host</strong>')
+  })
+
+  it('should render plain text without escaping', () => {
+    const text = renderTranscript('Caching', messages, 'txt')
+
+    expect(text).toContain('[Host] (2024-01-15 10:00:00)\nLet us discuss <caching>')
+    expect(text.indexOf('[Host]')).toBeLessThan(text.indexOf('[Participant]'))
+  })
+})
+
+describe('executeExport', () => {
+  const mockCouncil = {
+    getState: vi.fn(),
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    mockCouncil.getState.mockReturnValue({ topic: 'Caching', rounds: [{ messages }] })
+    vi.mocked(getCouncil).mockReturnValue(mockCouncil as any)
+  })
+
+  it('should return the transcript inline', async () => {
+    const result = await executeExport({ format: 'txt' })
+
+    expect(result.success).toBe(true)
+    expect(result.content).toContain('[Participant]')
+  })
+
+  it('should write the transcript to the output path', async () => {
+    const { writeFile } = await import('node:fs/promises')
+
+    const result = await executeExport({ outputPath: '/tmp/council.md' })
+
+    expect(writeFile).toHaveBeenCalledWith('/tmp/council.md', expect.stringContaining('# Caching'), 'utf-8')
+    expect(result.success).toBe(true)
+    expect(result.content).toBeUndefined()
+    expect(result.message).toBe('Exported 2 messages to /tmp/council.md')
+  })
+
+  it('should fail when there is nothing to export', async () => {
+    mockCouncil.getState.mockReturnValue({ topic: '', rounds: [] })
+
+    const result = await executeExport({})
+
+    expect(result.success).toBe(false)
+    expect(result.message).toBe('There are no messages to export yet.')
+  })
+})
@@ -0,0 +1,155 @@
+/**
+ * Council Export Tool
+ *
+ * Tool for exporting the discussion transcript
+ */
+
+import { writeFile } from 'node:fs/promises'
+import { z } from 'zod'
+import { getCouncil } from '../core/council'
+import { t } from '../i18n'
+import { formatDate } from '../utils'
+import type { Message } from '../types'
+
+/**
+ * Export tool input schema
+ */
+export const exportInputSchema = z.object({
+  format: z.enum(['markdown', 'html', 'txt']).optional().default('markdown').describe('Transcript format: markdown, html or txt'),
+  outputPath: z.string().optional().describe('File to write the transcript to; returned inline when omitted'),
+})
+
+export type ExportFormat = 'markdown' | 'html' | 'txt'
+
+export type ExportInput = {
+  format?: ExportFormat
+  outputPath?: string
+}
+
+/**
+ * Export tool output
+ */
+export interface ExportOutput {
+  success: boolean
+  message: string
+  format: ExportFormat
+  /** Rendered transcript, when no output path was given */
+  content?: string
+  outputPath?: string
+}
+
+const MENTION_PATTERN = /(@[\w-]+(?:\.[\w-]+)*)/
+
+const escapeMarkdown = (text: string) => text.replace(/([\\`*_{}[\]<>()#+!|~-])/g, '\\$1')
+
+const escapeHtml = (text: string) =>
+  text
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+
+/**
+ * Escape message content and render @mentions in bold
+ */
+function renderContent(content: string, escape: (text: string) => string, bold: (text: string) => string): string {
+  return content
+    .split(MENTION_PATTERN)
+    .map((part, i) => (i % 2 === 1 ? bold(escape(part)) : escape(part)))
+    .join('')
+}
+
+/**
+ * Render messages as a transcript in the given format
+ */
+export function renderTranscript(topic: string, messages: Message[], format: ExportFormat): string {
+  const ordered = [...messages].sort((a, b) => a.timestamp.getTime() - b.timestamp.getTime())
+
+  switch (format) {
+    case 'markdown':
+      return [
+        `# ${escapeMarkdown(topic)}`,
+        ...ordered.map(m =>
+          `### [${escapeMarkdown(m.from)}] (${formatDate(m.timestamp)})\n\n` +
+          renderContent(m.content, escapeMarkdown, s => `**${s}**`)
+        ),
+      ].join('\n\n') + '\n'
+    case 'html':
+      return [
+        '<!DOCTYPE html>',
+        '<html>',
+        `<head><meta charset="utf-8"><title>${escapeHtml(topic)}</title></head>`,
+        '<body>',
+        `<h1>${escapeHtml(topic)}</h1>`,
+        ...ordered.map(m =>
+          `<h3>[${escapeHtml(m.from)}] (${formatDate(m.timestamp)})</h3>\n` +
+          `<p>${renderContent(m.content, escapeHtml, s => `<strong>${s}</strong>`).replace(/\n/g, '<br>\n')}</p>`
+        ),
+        '</body>',
+        '</html>',
+      ].join('\n') + '\n'
+    case 'txt':
+      return [
+        topic,
+        ...ordered.map(m => `[${m.from}] (${formatDate(m.timestamp)})\n${m.content}`),
+      ].join('\n\n') + '\n'
+  }
+}
+
+/**
+ * Execute the export tool
+ */
+export async function executeExport(input: ExportInput): Promise<ExportOutput> {
+  const council = getCouncil()
+  const state = council.getState()
+  const format = input.format ?? 'markdown'
+  const messages = state.rounds.flatMap(round => round.messages)
+
+  if (messages.length === 0) {
+    return {
+      success: false,
+      message: t('errors.nothingToExport'),
+      format,
+    }
+  }
+
+  const content = renderTranscript(state.topic, messages, format)
+
+  if (input.outputPath) {
+    try {
+      await writeFile(input.outputPath, content, 'utf-8')
+    } catch (error) {
+      return {
+        success: false,
+        message: error instanceof Error ? error.message : String(error),
+        format,
+      }
+    }
+
+    return {
+      success: true,
+      message: t('messages.exported', { count: messages.length, path: input.outputPath }),
+      format,
+      outputPath: input.outputPath,
+    }
+  }
+
+  return {
+    success: true,
+    message: t('messages.exportReady', { count: messages.length }),
+    format,
+    content,
+  }
+}
+
+/**
+ * Create the export tool definition for OpenCode plugin
+ */
+export function createExportTool() {
+  return {
+    name: 'council_export',
+    description: t('commands.export.description'),
+    parameters: exportInputSchema,
+    execute: executeExport,
+  }
+}
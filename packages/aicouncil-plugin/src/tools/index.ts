@@ -10,6 +10,9 @@ import { createStatusTool, executeStatus, statusInputSchema, type StatusInput, t
 import { createModelsTool, executeModels, modelsInputSchema, type ModelsInput, type ModelsOutput } from './models'
 import { createEndTool, executeEnd, endInputSchema, type EndInput, type EndOutput } from './end'
 import { createNextTool, executeNext, nextInputSchema, type NextInput, type NextOutput } from './next'
+import { createExportTool, executeExport, exportInputSchema, type ExportInput, type ExportOutput } from './export'
+import { createAddTool, executeAdd, addInputSchema, type AddInput, type AddOutput } from './add'
+import { createRemoveTool, executeRemove, removeInputSchema, type RemoveInput, type RemoveOutput } from './remove'
 
 // Re-export everything
 export { createSetupTool, executeSetup, setupInputSchema, type SetupInput, type SetupOutput }
@@ -18,6 +21,9 @@ export { createStatusTool, executeStatus, statusInputSchema, type StatusInput, t
 export { createModelsTool, executeModels, modelsInputSchema, type ModelsInput, type ModelsOutput }
 export { createEndTool, executeEnd, endInputSchema, type EndInput, type EndOutput }
 export { createNextTool, executeNext, nextInputSchema, type NextInput, type NextOutput }
+export { createExportTool, executeExport, exportInputSchema, type ExportInput, type ExportOutput }
+export { createAddTool, executeAdd, addInputSchema, type AddInput, type AddOutput }
+export { createRemoveTool, executeRemove, removeInputSchema, type RemoveInput, type RemoveOutput }
 
 /**
  * Create all tools for the plugin
@@ -30,5 +36,8 @@ export function createAllTools() {
     createModelsTool(),
     createEndTool(),
     createNextTool(),
+    createExportTool(),
+    createAddTool(),
+    createRemoveTool(),
   ]
 }
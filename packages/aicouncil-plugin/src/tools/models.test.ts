@@ -1,5 +1,8 @@
-import { describe, it, expect } from 'vitest'
-import { executeModels, modelsInputSchema } from './models'
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
+import { executeModels, modelsInputSchema, testModel } from './models'
+import { Council, getCouncil, resetCouncil } from '../core/council'
+import { PREDEFINED_PROVIDERS, providerAdapter } from '../providers/adapter'
+import { ProviderError } from '../providers/errors'
 
 describe('modelsInputSchema', () => {
   it('should validate empty input', () => {
@@ -10,10 +13,14 @@ describe('modelsInputSchema', () => {
 })
 
 describe('executeModels', () => {
+  beforeEach(() => {
+    resetCouncil()
+  })
+
   it('should return list of predefined models', async () => {
     const result = await executeModels({})
 
-    expect(result.predefined).toHaveLength(4)
+    expect(result.predefined).toHaveLength(7)
     expect(result.customSupported).toBe(true)
     expect(result.message).toBeDefined()
   })
@@ -54,4 +61,117 @@ describe('executeModels', () => {
     expect(openai?.name).toBe('GPT-4o')
     expect(openai?.defaultModelId).toBe('gpt-4o')
   })
+
+  it('should include Gemini model info', async () => {
+    const result = await executeModels({})
+
+    const google = result.predefined.find(m => m.providerId === 'google')
+    expect(google).toBeDefined()
+    expect(google?.name).toBe('Gemini')
+    expect(google?.defaultModelId).toBe('gemini-2.5-pro')
+  })
+
+  it('should include DeepSeek model info', async () => {
+    const result = await executeModels({})
+
+    const deepseek = result.predefined.find(m => m.providerId === 'deepseek')
+    expect(deepseek).toBeDefined()
+    expect(deepseek?.name).toBe('DeepSeek')
+    expect(deepseek?.defaultModelId).toBe('deepseek-chat')
+  })
+
+  it('should list Ollama as not needing an API key', async () => {
+    const result = await executeModels({})
+
+    const ollama = result.predefined.find(m => m.providerId === 'ollama')
+    expect(ollama?.defaultModelId).toBe('llama3.2')
+    expect(ollama?.requiresApiKey).toBe(false)
+  })
+
+  it('should list configured council models and hide matching presets', async () => {
+    const council = getCouncil()
+    council.addParticipant(PREDEFINED_PROVIDERS.kimi('kimi-key'), { isHost: true })
+    council.addParticipant({ ...PREDEFINED_PROVIDERS.openai('openai-key', 'gpt-4.1'), name: 'GPT-4.1' })
+
+    const result = await executeModels({})
+
+    expect(result.configured).toHaveLength(2)
+    expect(result.configured[0]).toMatchObject({ providerId: 'kimi', modelId: 'kimi-for-coding', isHost: true })
+    expect(result.configured[1]).toMatchObject({ name: 'GPT-4.1', modelId: 'gpt-4.1', isHost: false })
+    expect(result.predefined.find(m => m.providerId === 'kimi')).toBeUndefined()
+    expect(result.predefined.find(m => m.providerId === 'openai')).toBeDefined()
+  })
+
+  it('should only list configured models when configuredOnly is set', async () => {
+    getCouncil().addParticipant(PREDEFINED_PROVIDERS.kimi('kimi-key'))
+
+    const result = await executeModels({ configuredOnly: true })
+
+    expect(result.configured).toHaveLength(1)
+    expect(result.predefined).toEqual([])
+  })
+})
+
+describe('executeModels with test', () => {
+  beforeEach(() => {
+    resetCouncil()
+    const council = getCouncil()
+    council.addParticipant(PREDEFINED_PROVIDERS.kimi('kimi-key'), { isHost: true })
+    council.addParticipant(PREDEFINED_PROVIDERS.minimax('bad-token'))
+  })
+
+  afterEach(() => {
+    vi.restoreAllMocks()
+  })
+
+  it('should test every configured model and classify failures', async () => {
+    const call = vi.spyOn(providerAdapter, 'call').mockImplementation(async participant => {
+      if (participant.provider.id === 'minimax') {
+        throw new ProviderError('MiniMax API error: 429 - slow down', 'rate_limit', 429)
+      }
+      return { content: ' OK\n' }
+    })
+
+    const result = await executeModels({ test: true })
+
+    expect(call).toHaveBeenCalledTimes(2)
+    expect(call.mock.calls[0][1]).toBe('Reply with OK.')
+    expect(call.mock.calls[0][2]).toMatchObject({ retries: 0 })
+    expect(result.tests).toEqual([
+      expect.objectContaining({ name: 'Kimi For Coding', ok: true, reply: 'OK' }),
+      expect.objectContaining({ name: 'MiniMax M2.1', ok: false, errorKind: 'rate_limit' }),
+    ])
+    expect(result.tests![0].latencyMs).toBeGreaterThanOrEqual(0)
+    expect(result.message).toBe('1 of 2 models responded')
+  })
+
+  it('should ping through the council\'s adapter with room for reasoning', async () => {
+    const adapter = { setClient: vi.fn(), call: vi.fn().mockResolvedValue({ content: 'OK' }) }
+    const council = new Council({}, adapter)
+    const participant = council.addParticipant(PREDEFINED_PROVIDERS.kimi('kimi-key'), { isHost: true })
+    const shared = vi.spyOn(providerAdapter, 'call')
+
+    const result = await testModel(participant, council)
+
+    expect(result.ok).toBe(true)
+    expect(shared).not.toHaveBeenCalled()
+    expect(adapter.call.mock.calls[0][2]).toMatchObject({ maxTokens: 1024, retries: 0 })
+    expect(adapter.call.mock.calls[0][2]).not.toHaveProperty('enforceSchema')
+  })
+
+  it('should test only the named model', async () => {
+    const call = vi.spyOn(providerAdapter, 'call').mockResolvedValue({ content: 'OK' })
+
+    const result = await executeModels({ test: true, model: 'minimax' })
+
+    expect(call).toHaveBeenCalledTimes(1)
+    expect(result.tests?.map(r => r.name)).toEqual(['MiniMax M2.1'])
+  })
+
+  it('should report an unknown model', async () => {
+    const result = await executeModels({ test: true, model: 'gpt-4o' })
+
+    expect(result.tests).toEqual([])
+    expect(result.message).toBe('Model not found: gpt-4o')
+  })
 })
@@ -6,14 +6,25 @@
 
 import { z } from 'zod'
 import { PREDEFINED_PROVIDERS } from '../providers/adapter'
+import { ProviderError, type ProviderErrorKind } from '../providers/errors'
+import { getCouncil, type Council } from '../core/council'
 import { t } from '../i18n'
+import type { Participant } from '../types'
 
 /**
  * Models tool input schema
  */
-export const modelsInputSchema = z.object({})
+export const modelsInputSchema = z.object({
+  configuredOnly: z.boolean().optional().default(false).describe('Only list models configured in the current council'),
+  test: z.boolean().optional().default(false).describe('Send each configured model a short request to check its key and endpoint'),
+  model: z.string().optional().describe('Only test the model with this name, participant ID or provider ID'),
+})
 
-export type ModelsInput = z.infer<typeof modelsInputSchema>
+export type ModelsInput = {
+  configuredOnly?: boolean
+  test?: boolean
+  model?: string
+}
 
 /**
  * Model info
@@ -26,20 +37,98 @@ export interface ModelInfo {
   requiresApiKey: boolean
 }
 
+/**
+ * A model configured in the current council
+ */
+export interface ConfiguredModelInfo {
+  participantId: string
+  name: string
+  providerId: string
+  modelId: string
+  isHost: boolean
+}
+
+/**
+ * Outcome of a test request to a configured model
+ */
+export interface ModelTestResult {
+  participantId: string
+  name: string
+  ok: boolean
+  latencyMs: number
+  /** The model's reply, when it answered */
+  reply?: string
+  error?: string
+  errorKind?: ProviderErrorKind
+}
+
 /**
  * Models tool output
  */
 export interface ModelsOutput {
+  configured: ConfiguredModelInfo[]
+  /** Results of test requests, when `test` was set */
+  tests?: ModelTestResult[]
+  /** Presets not already configured in the council */
   predefined: ModelInfo[]
   customSupported: boolean
   message: string
 }
 
+/**
+ * Timeout for a model test request in milliseconds
+ */
+const TEST_TIMEOUT = 30000
+
+/**
+ * Token cap for a model test request; reasoning models spend part of it
+ * thinking before they answer, so a tight cap comes back empty
+ */
+const TEST_MAX_TOKENS = 1024
+
+/**
+ * Send a model a minimal request and report how it went
+ */
+export async function testModel(participant: Participant, council: Council = getCouncil()): Promise<ModelTestResult> {
+  const startedAt = Date.now()
+  try {
+    const response = await council.probe(participant, t('prompts.pingPrompt'), {
+      maxTokens: TEST_MAX_TOKENS,
+      retries: 0,
+      timeout: TEST_TIMEOUT,
+    })
+    return {
+      participantId: participant.id,
+      name: participant.name,
+      ok: true,
+      latencyMs: Date.now() - startedAt,
+      reply: response.content.trim(),
+    }
+  } catch (error) {
+    return {
+      participantId: participant.id,
+      name: participant.name,
+      ok: false,
+      latencyMs: Date.now() - startedAt,
+      error: error instanceof Error ? error.message : String(error),
+      errorKind: error instanceof ProviderError ? error.kind : 'unknown',
+    }
+  }
+}
+
 /**
  * Execute the models tool
  */
-export async function executeModels(_input: ModelsInput): Promise<ModelsOutput> {
-  const predefined: ModelInfo[] = [
+export async function executeModels(input: ModelsInput = {}): Promise<ModelsOutput> {
+  const configured: ConfiguredModelInfo[] = getCouncil().participants.map(p => ({
+    participantId: p.id,
+    name: p.name,
+    providerId: p.provider.id,
+    modelId: p.provider.modelId,
+    isHost: p.isHost,
+  }))
+
+  const presets: ModelInfo[] = [
     {
       providerId: 'kimi',
       name: 'Kimi For Coding',
@@ -68,12 +157,69 @@ export async function executeModels(_input: ModelsInput): Promise<ModelsOutput>
       defaultModelId: 'gpt-4o',
       requiresApiKey: true,
     },
+    {
+      providerId: 'google',
+      name: 'Gemini',
+      description: 'Google Gemini models (gemini-2.5-pro, gemini-2.5-flash)',
+      defaultModelId: 'gemini-2.5-pro',
+      requiresApiKey: true,
+    },
+    {
+      providerId: 'deepseek',
+      name: 'DeepSeek',
+      description: 'DeepSeek models via the OpenAI-compatible API (deepseek-chat, deepseek-reasoner)',
+      defaultModelId: 'deepseek-chat',
+      requiresApiKey: true,
+    },
+    {
+      providerId: 'ollama',
+      name: 'Ollama',
+      description: 'Local models served by Ollama at http://localhost:11434',
+      defaultModelId: 'llama3.2',
+      requiresApiKey: false,
+    },
   ]
 
+  const predefined = input.configuredOnly
+    ? []
+    : presets.filter(preset => !configured.some(
+        m => m.providerId === preset.providerId && m.modelId === preset.defaultModelId
+      ))
+
+  if (!input.test) {
+    return {
+      configured,
+      predefined,
+      customSupported: true,
+      message: t('commands.models.description'),
+    }
+  }
+
+  // Test all configured models at once, or just the one asked for
+  const key = input.model?.toLowerCase()
+  const targets = getCouncil().participants.filter(p =>
+    !key || [p.id, p.name, p.provider.id].some(value => value.toLowerCase() === key)
+  )
+  if (targets.length === 0) {
+    return {
+      configured,
+      predefined,
+      tests: [],
+      customSupported: true,
+      message: input.model ? t('errors.modelNotFound', { model: input.model }) : t('errors.noActiveDiscussion'),
+    }
+  }
+
+  const tests = await Promise.all(targets.map(p => testModel(p)))
   return {
+    configured,
     predefined,
+    tests,
     customSupported: true,
-    message: t('commands.models.description'),
+    message: t('messages.modelsTested', {
+      passed: tests.filter(r => r.ok).length,
+      total: tests.length,
+    }),
   }
 }
 
@@ -31,6 +31,7 @@ describe('executeNext', () => {
     isComplete: false,
     currentRound: 1,
     nextRound: vi.fn(),
+    runCommand: vi.fn(),
     on: vi.fn().mockReturnValue(unsubscribeMock),
     participants: [
       { id: 'p1', name: 'Host', isHost: true },
@@ -108,4 +109,15 @@ describe('executeNext', () => {
 
     expect(result.responses).toHaveLength(2)
   })
+
+  it('should run a slash command instead of a round', async () => {
+    mockCouncil.runCommand.mockResolvedValue({ ok: true, message: 'The round limit is now 8 (0 means unlimited).' })
+
+    const result = await executeNext({ additionalContext: '/rounds 8' })
+
+    expect(mockCouncil.runCommand).toHaveBeenCalledWith('/rounds 8')
+    expect(mockCouncil.nextRound).not.toHaveBeenCalled()
+    expect(result.success).toBe(true)
+    expect(result.message).toContain('round limit is now 8')
+  })
 })
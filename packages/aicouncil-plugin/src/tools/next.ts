@@ -6,6 +6,7 @@
 
 import { z } from 'zod'
 import { getCouncil } from '../core/council'
+import { isCommand } from '../core/commands'
 import { t } from '../i18n'
 import type { Message } from '../types'
 
@@ -13,7 +14,7 @@ import type { Message } from '../types'
  * Next tool input schema
  */
 export const nextInputSchema = z.object({
-  additionalContext: z.string().optional().describe('Additional context or guidance for the next round'),
+  additionalContext: z.string().optional().describe('Additional context or guidance for the next round, or a command instead of a round: /done, /summary, /kick <name>, /add <name>, /rounds <number>'),
 })
 
 export type NextInput = {
@@ -51,6 +52,28 @@ export async function executeNext(input: NextInput): Promise<NextOutput> {
     }
   }
 
+  // A slash command acts on the discussion instead of running a round
+  if (input.additionalContext && isCommand(input.additionalContext)) {
+    try {
+      const result = await council.runCommand(input.additionalContext)
+      return {
+        success: result.ok,
+        message: result.message,
+        round: council.currentRound,
+        responses: [],
+        isComplete: council.isComplete,
+      }
+    } catch (error) {
+      return {
+        success: false,
+        message: error instanceof Error ? error.message : String(error),
+        round: council.currentRound,
+        responses: [],
+        isComplete: false,
+      }
+    }
+  }
+
   const responses: NextOutput['responses'] = []
 
   // Set up callback to collect responses
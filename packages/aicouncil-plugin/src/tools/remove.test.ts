@@ -0,0 +1,65 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest'
+import { executeRemove, removeInputSchema } from './remove'
+import { getCouncil } from '../core/council'
+
+vi.mock('../core/council', async () => {
+  const actual = await vi.importActual('../core/council')
+  return {
+    ...actual,
+    getCouncil: vi.fn(),
+  }
+})
+
+describe('removeInputSchema', () => {
+  it('should require a participant', () => {
+    expect(removeInputSchema.safeParse({ participant: 'Reviewer' }).success).toBe(true)
+    expect(removeInputSchema.safeParse({}).success).toBe(false)
+  })
+})
+
+describe('executeRemove', () => {
+  const host = { id: 'p1', name: 'Host', isHost: true }
+  const guest = { id: 'p2', name: 'Guest', isHost: false }
+  let participants: typeof host[]
+  const mockCouncil = {
+    get participants() {
+      return participants
+    },
+    findParticipant: vi.fn((key: string) =>
+      participants.find(p => p.id === key || p.name.toLowerCase() === key.toLowerCase())
+    ),
+    removeParticipant: vi.fn((id: string) => {
+      participants = participants.filter(p => p.id !== id)
+      return true
+    }),
+  }
+
+  beforeEach(() => {
+    vi.clearAllMocks()
+    participants = [host, guest]
+    vi.mocked(getCouncil).mockReturnValue(mockCouncil as any)
+  })
+
+  it('should remove a participant by name', async () => {
+    const result = await executeRemove({ participant: 'guest' })
+
+    expect(result.success).toBe(true)
+    expect(result.message).toBe('Guest left the discussion')
+    expect(mockCouncil.removeParticipant).toHaveBeenCalledWith('p2')
+    expect(result.remaining).toEqual(['Host'])
+  })
+
+  it('should refuse to remove the host', async () => {
+    const result = await executeRemove({ participant: 'Host' })
+
+    expect(result.success).toBe(false)
+    expect(mockCouncil.removeParticipant).not.toHaveBeenCalled()
+  })
+
+  it('should report an unknown participant', async () => {
+    const result = await executeRemove({ participant: 'Nobody' })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('Nobody')
+  })
+})
@@ -0,0 +1,77 @@
+/**
+ * Council Remove Tool
+ *
+ * Tool for taking a model out of the council, including mid-discussion
+ */
+
+import { z } from 'zod'
+import { getCouncil } from '../core/council'
+import { t } from '../i18n'
+
+/**
+ * Remove tool input schema
+ */
+export const removeInputSchema = z.object({
+  participant: z.string().describe('Name or participant ID of the model to remove'),
+})
+
+export type RemoveInput = {
+  participant: string
+}
+
+/**
+ * Remove tool output
+ */
+export interface RemoveOutput {
+  success: boolean
+  message: string
+  /** Names of the participants still in the council */
+  remaining: string[]
+}
+
+/**
+ * Execute the remove tool
+ *
+ * The host can't be removed. A participant removed mid-discussion can be
+ * brought back with the `/add` command.
+ */
+export async function executeRemove(input: RemoveInput): Promise<RemoveOutput> {
+  const council = getCouncil()
+  const remaining = () => council.participants.map(p => p.name)
+
+  const participant = council.findParticipant(input.participant)
+  if (!participant) {
+    return {
+      success: false,
+      message: t('errors.modelNotFound', { model: input.participant }),
+      remaining: remaining(),
+    }
+  }
+
+  if (participant.isHost) {
+    return {
+      success: false,
+      message: t('errors.cannotRemoveHost', { name: participant.name }),
+      remaining: remaining(),
+    }
+  }
+
+  council.removeParticipant(participant.id)
+  return {
+    success: true,
+    message: t('participant.left', { name: participant.name }),
+    remaining: remaining(),
+  }
+}
+
+/**
+ * Create the remove tool definition for OpenCode plugin
+ */
+export function createRemoveTool() {
+  return {
+    name: 'council_remove',
+    description: t('commands.remove.description'),
+    parameters: removeInputSchema,
+    execute: executeRemove,
+  }
+}
@@ -1,6 +1,7 @@
 import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
 import { executeSetup, setupInputSchema } from './setup'
 import { getCouncil, resetCouncil } from '../core/council'
+import { providerAdapter } from '../providers/adapter'
 
 // Mock the council module
 vi.mock('../core/council', async () => {
@@ -72,6 +73,9 @@ describe('setupInputSchema', () => {
 describe('executeSetup', () => {
   const mockCouncil = {
     addParticipant: vi.fn(),
+    setCoordinator: vi.fn(),
+    on: vi.fn(),
+    participants: [] as any[],
     discussionId: 'test-council-id',
   }
 
@@ -226,4 +230,350 @@ describe('executeSetup', () => {
       expect.any(Object)
     )
   })
+
+  it('should explain how to add models when none are given', async () => {
+    const missing = await executeSetup({} as any)
+    const empty = await executeSetup({ models: [] })
+
+    for (const result of [missing, empty]) {
+      expect(result.success).toBe(false)
+      expect(result.message).toContain('No models were given')
+      expect(result.message).toContain('council_models')
+    }
+    expect(resetCouncil).not.toHaveBeenCalled()
+  })
+
+  it('should reject a single model', async () => {
+    const result = await executeSetup({ models: [{ providerId: 'kimi' }] })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('At least 2 models are required')
+  })
+
+  it('should install a moderator chosen by name', async () => {
+    mockCouncil.participants = [
+      { id: 'participant-kimi', name: 'Kimi For Coding', isHost: true, provider: { id: 'kimi' } },
+      { id: 'participant-minimax', name: 'MiniMax M2.1', isHost: false, provider: { id: 'minimax' } },
+    ]
+
+    const result = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax' }],
+      moderator: 'minimax',
+    })
+
+    expect(result.success).toBe(true)
+    expect(mockCouncil.setCoordinator).toHaveBeenCalledWith(expect.any(Function))
+  })
+
+  it('should reject an unknown summarizer before resetting the council', async () => {
+    const result = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax', name: 'Critic' }],
+      summarizer: 'gpt-4o',
+    })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('Model not found: gpt-4o')
+    expect(resetCouncil).not.toHaveBeenCalled()
+  })
+
+  it('should accept a summarizer given by name or provider ID', async () => {
+    const models = [{ providerId: 'kimi' }, { providerId: 'minimax', name: 'Critic' }]
+
+    expect((await executeSetup({ models, summarizer: 'critic' })).success).toBe(true)
+    expect((await executeSetup({ models, summarizer: 'KIMI' })).success).toBe(true)
+  })
+
+  it('should reject an unknown moderator before resetting the council', async () => {
+    mockCouncil.participants = []
+
+    const result = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax' }],
+      moderator: 'gpt-4o',
+    })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('Model not found: gpt-4o')
+    expect(mockCouncil.setCoordinator).not.toHaveBeenCalled()
+    expect(resetCouncil).not.toHaveBeenCalled()
+    expect(mockCouncil.addParticipant).not.toHaveBeenCalled()
+  })
+
+  it('should accept the host as moderator', async () => {
+    mockCouncil.participants = [
+      { id: 'participant-kimi', name: 'Kimi For Coding', isHost: true, provider: { id: 'kimi' } },
+      { id: 'participant-minimax', name: 'MiniMax M2.1', isHost: false, provider: { id: 'minimax' } },
+    ]
+
+    const result = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax' }],
+      moderator: 'Host',
+    })
+
+    expect(result.success).toBe(true)
+    expect(mockCouncil.setCoordinator).toHaveBeenCalledWith(expect.any(Function))
+  })
+
+  it('should attach the live display only when asked', async () => {
+    const models = [{ providerId: 'kimi' }, { providerId: 'minimax' }]
+
+    await executeSetup({ models })
+    expect(mockCouncil.on).not.toHaveBeenCalled()
+
+    await executeSetup({ models, liveDisplay: true })
+    expect(mockCouncil.on).toHaveBeenCalledWith('message:new', expect.any(Function))
+  })
+
+  it('should pass maxTokens through to the provider config', async () => {
+    const input = {
+      models: [
+        { providerId: 'kimi', isHost: true, maxTokens: 8192 },
+        { providerId: 'minimax' },
+      ],
+    }
+
+    await executeSetup(input)
+
+    expect(mockCouncil.addParticipant).toHaveBeenNthCalledWith(
+      1,
+      expect.objectContaining({ id: 'kimi', maxTokens: 8192 }),
+      expect.any(Object)
+    )
+  })
+
+  it('should reject a non-positive maxTokens', async () => {
+    const input = {
+      models: [
+        { providerId: 'kimi', isHost: true, maxTokens: 0 },
+        { providerId: 'minimax' },
+      ],
+    }
+
+    const result = await executeSetup(input)
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('maxTokens for kimi must be a positive integer')
+    expect(resetCouncil).not.toHaveBeenCalled()
+  })
+
+  it('should pass the default response timeout to the council', async () => {
+    const input = {
+      models: [
+        { providerId: 'kimi', isHost: true },
+        { providerId: 'minimax', timeout: 300000 },
+      ],
+      responseTimeout: 90000,
+    }
+
+    await executeSetup(input)
+
+    expect(getCouncil).toHaveBeenCalledWith(expect.objectContaining({ responseTimeout: 90000 }))
+    expect(mockCouncil.addParticipant).toHaveBeenNthCalledWith(
+      2,
+      expect.objectContaining({ timeout: 300000 }),
+      expect.any(Object)
+    )
+  })
+
+  it('should reject a non-positive response timeout', async () => {
+    const input = {
+      models: [
+        { providerId: 'kimi', isHost: true },
+        { providerId: 'minimax' },
+      ],
+      responseTimeout: -1,
+    }
+
+    const result = await executeSetup(input)
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('responseTimeout must be a positive number of milliseconds, got -1')
+    expect(resetCouncil).not.toHaveBeenCalled()
+  })
+
+  it('should pass sampling controls through to the provider config', async () => {
+    const input = {
+      models: [
+        { providerId: 'kimi', isHost: true, temperature: 0 },
+        { providerId: 'minimax', temperature: 1.2, topP: 0.9 },
+      ],
+    }
+
+    await executeSetup(input)
+
+    expect(mockCouncil.addParticipant).toHaveBeenNthCalledWith(
+      1,
+      expect.objectContaining({ temperature: 0 }),
+      expect.any(Object)
+    )
+    expect(mockCouncil.addParticipant).toHaveBeenNthCalledWith(
+      2,
+      expect.objectContaining({ temperature: 1.2, topP: 0.9 }),
+      expect.any(Object)
+    )
+  })
+
+  it('should reject out-of-range sampling controls', async () => {
+    const hot = await executeSetup({
+      models: [{ providerId: 'kimi', temperature: 3 }, { providerId: 'minimax' }],
+    })
+    const wide = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax', topP: 1.5 }],
+    })
+
+    expect(hot.success).toBe(false)
+    expect(hot.message).toContain('temperature for kimi must be between 0 and 2')
+    expect(wide.success).toBe(false)
+    expect(wide.message).toContain('topP for minimax must be between 0 and 1')
+  })
+
+  it('should pass Azure deployment settings through to the provider config', async () => {
+    const result = await executeSetup({
+      models: [
+        {
+          providerId: 'azure',
+          name: 'Azure GPT-4o',
+          baseURL: 'https://my-resource.openai.azure.com',
+          apiKey: 'azure-key',
+          modelId: 'gpt-4o',
+          deployment: 'gpt4o-prod',
+          apiVersion: '2024-06-01',
+        },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.success).toBe(true)
+    expect(mockCouncil.addParticipant).toHaveBeenCalledWith(
+      expect.objectContaining({ id: 'azure', deployment: 'gpt4o-prod', apiVersion: '2024-06-01' }),
+      expect.anything()
+    )
+  })
+
+  it('should reject a malformed or non-HTTP proxy', async () => {
+    const malformed = await executeSetup({
+      models: [{ providerId: 'kimi', proxy: 'not a url' }, { providerId: 'minimax' }],
+    })
+    const socks = await executeSetup({
+      models: [{ providerId: 'kimi' }, { providerId: 'minimax', proxy: 'socks5://127.0.0.1:1080' }],
+    })
+
+    expect(malformed.success).toBe(false)
+    expect(malformed.message).toContain('proxy for kimi must be an http:// or https:// URL, got not a url')
+    expect(socks.success).toBe(false)
+    expect(socks.message).toContain('proxy for minimax must be an http:// or https:// URL')
+    expect(socks.message).toContain('SOCKS proxies are not supported')
+  })
+
+  it('should reject a malformed response schema', async () => {
+    const result = await executeSetup({
+      models: [
+        { providerId: 'openai', responseSchema: { type: 'object', required: 'verdict' } as any },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('responseSchema for openai is not a usable JSON schema: $.required must be an array of property names')
+  })
+
+  it('should require a resource URL for Azure models', async () => {
+    const result = await executeSetup({
+      models: [{ providerId: 'azure', modelId: 'gpt-4o' }, { providerId: 'minimax' }],
+    })
+
+    expect(result.success).toBe(false)
+    expect(result.message).toContain('baseURL for azure must be the Azure OpenAI resource URL')
+  })
+
+  it('should warn about direct-only settings when calls go through the OpenCode client', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+
+    const result = await executeSetup({
+      models: [
+        { providerId: 'kimi', maxTokens: 8192 },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.success).toBe(true)
+    expect(result.warnings).toEqual([
+      'maxTokens for kimi only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+  })
+
+  it('should warn about council settings the OpenCode client ignores', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+    const models = [{ providerId: 'kimi' }, { providerId: 'minimax' }]
+
+    const result = await executeSetup({ models, maxContinuations: 2, keepRawResponses: true, showThinking: true })
+    expect(result.warnings).toEqual([
+      'maxContinuations only applies to direct API calls and is ignored through the OpenCode client',
+      'keepRawResponses only applies to direct API calls and is ignored through the OpenCode client',
+      'showThinking only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+
+    const off = await executeSetup({ models, maxContinuations: 0, showThinking: false })
+    expect(off.warnings).toBeUndefined()
+  })
+
+  it('should not warn on direct API calls', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(false)
+
+    const result = await executeSetup({
+      models: [
+        { providerId: 'kimi', maxTokens: 8192 },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.warnings).toBeUndefined()
+  })
+
+  it('should warn about sampling controls the OpenCode client would drop', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+
+    const result = await executeSetup({
+      models: [
+        { providerId: 'kimi', temperature: 0.2, topP: 0.9 },
+        { providerId: 'minimax', name: 'Critic', temperature: 1 },
+      ],
+    })
+
+    expect(result.warnings).toEqual([
+      'temperature for kimi only applies to direct API calls and is ignored through the OpenCode client',
+      'topP for kimi only applies to direct API calls and is ignored through the OpenCode client',
+      'temperature for Critic only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+  })
+
+  it('should warn that a per-model proxy is ignored through the OpenCode client', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+
+    const result = await executeSetup({
+      models: [
+        { providerId: 'kimi', proxy: 'http://proxy.corp:8080' },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.warnings).toEqual([
+      'proxy for kimi only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+  })
+
+  it('should warn that custom headers are ignored through the OpenCode client', async () => {
+    vi.spyOn(providerAdapter, 'hasClient', 'get').mockReturnValue(true)
+
+    const result = await executeSetup({
+      models: [
+        { providerId: 'openai', headers: { 'OpenAI-Organization': 'org-123' }, overrideHeaders: true },
+        { providerId: 'minimax' },
+      ],
+    })
+
+    expect(result.warnings).toEqual([
+      'headers for openai only applies to direct API calls and is ignored through the OpenCode client',
+      'overrideHeaders for openai only applies to direct API calls and is ignored through the OpenCode client',
+    ])
+  })
 })
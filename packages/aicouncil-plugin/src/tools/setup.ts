@@ -6,37 +6,132 @@
 
 import { z } from 'zod'
 import { getCouncil, resetCouncil } from '../core/council'
-import { createProviderConfig, PREDEFINED_PROVIDERS } from '../providers/adapter'
+import { createModerator } from '../core/moderator'
+import { attachStreamingDisplay, createTerminalRenderer } from '../core/display'
+import { createProviderConfig, PREDEFINED_PROVIDERS, providerAdapter } from '../providers/adapter'
 import { t } from '../i18n'
-import type { ProviderConfig } from '../types'
+import { logger, jsonSchemaError } from '../utils'
+import type { ProviderConfig, JsonSchema, ModelPricing, LogLevel } from '../types'
+
+/**
+ * Schema for one model's settings, shared by council_setup and council_add
+ */
+export const modelInputSchema = z.object({
+  providerId: z.string().describe('Provider ID (e.g., "kimi", "minimax", "anthropic")'),
+  modelId: z.string().optional().describe('Model ID (optional, uses default if not specified)'),
+  name: z.string().optional().describe('Display name for this participant'),
+  apiKey: z.string().optional().describe('API key (optional, uses configured key if not specified)'),
+  baseURL: z.string().optional().describe('Base URL (optional, uses default if not specified)'),
+  isHost: z.boolean().optional().describe('Whether this model should be the host'),
+  firstTurnPrompt: z.string().optional().describe('Instruction for an introduction this model posts once when the discussion starts'),
+  maxTokens: z.number().int().positive().optional().describe('Maximum tokens this model may generate per reply (direct API calls only; through OpenCode the model\'s OpenCode settings apply)'),
+  temperature: z.number().min(0).max(2).optional().describe('Sampling temperature (0-2); omit to use the provider default (direct API calls only)'),
+  topP: z.number().min(0).max(1).optional().describe('Nucleus sampling probability (0-1); omit to use the provider default (direct API calls only)'),
+  timeout: z.number().int().positive().optional().describe('Response timeout for this model in milliseconds'),
+  systemPrompt: z.string().optional().describe('Persona or role instructions for this model, added to its system prompt'),
+  includeSystemMessages: z.boolean().optional().describe('Whether system notices (joins, skipped speakers, failures) appear in this model\'s context; defaults to true'),
+  maxContextMessages: z.number().int().positive().optional().describe('Most recent messages included in this model\'s context (default 10)'),
+  maxContextTokens: z.number().int().positive().optional().describe('Rough token budget for this model\'s context; the oldest messages are dropped to fit'),
+  responseSchema: z.record(z.unknown()).optional().describe('JSON schema this model\'s round replies must match, using type, properties, required, items and enum; invalid replies are retried once'),
+  strictSchema: z.boolean().optional().describe('Send responseSchema in OpenAI strict mode; the schema must then require every property and set additionalProperties to false'),
+  pricing: z.object({
+    input: z.number().min(0),
+    output: z.number().min(0),
+  }).optional().describe('Price in USD per million input and output tokens, for the cost estimate'),
+  deployment: z.string().optional().describe('Azure OpenAI deployment name (defaults to the model ID)'),
+  apiVersion: z.string().optional().describe('Azure OpenAI REST API version, e.g. "2024-10-21"'),
+  headers: z.record(z.string()).optional().describe('Extra HTTP headers for this model\'s direct API calls, e.g. {"OpenAI-Organization": "org-123"}; built-in headers win unless overrideHeaders is set'),
+  overrideHeaders: z.boolean().optional().describe('Let headers replace built-in headers such as the auth header (direct API calls only)'),
+  proxy: z.string().optional().describe('HTTP(S) proxy URL for this model\'s direct API calls, e.g. "http://proxy.corp:8080" (SOCKS is not supported); defaults to HTTPS_PROXY. Requests through OpenCode use OpenCode\'s network settings'),
+})
 
 /**
  * Setup tool input schema
  */
 export const setupInputSchema = z.object({
-  models: z.array(z.object({
-    providerId: z.string().describe('Provider ID (e.g., "kimi", "minimax", "anthropic")'),
-    modelId: z.string().optional().describe('Model ID (optional, uses default if not specified)'),
-    name: z.string().optional().describe('Display name for this participant'),
-    apiKey: z.string().optional().describe('API key (optional, uses configured key if not specified)'),
-    baseURL: z.string().optional().describe('Base URL (optional, uses default if not specified)'),
-    isHost: z.boolean().optional().describe('Whether this model should be the host'),
-  })).min(2).describe('List of models to participate in the discussion'),
-  maxRounds: z.number().optional().default(5).describe('Maximum number of discussion rounds'),
+  models: z.array(modelInputSchema).min(2).describe('List of models to participate in the discussion'),
+  maxRounds: z.number().int().min(0).optional().default(5).describe('Maximum number of discussion rounds (0 for unlimited)'),
+  responseTimeout: z.number().int().positive().optional().describe('Default response timeout per model call in milliseconds; a model\'s own timeout overrides it'),
   locale: z.enum(['en', 'zh', 'zh-TW', 'ja', 'ko']).optional().default('en').describe('Language for messages'),
+  announceMembership: z.boolean().optional().describe('Post a system message when participants join or leave mid-discussion'),
+  duplicateReplyWindow: z.number().int().min(0).optional().describe('Suppress replies identical to one of the participant\'s last N replies (0 disables)'),
+  minResponseLength: z.number().int().min(0).optional().describe('Retry once when a reply is shorter than this many characters (0 disables)'),
+  onAllParticipantsFailed: z.enum(['continue', 'end']).optional().describe('Whether to keep going or end the discussion when every participant fails in a round'),
+  keepRawResponses: z.boolean().optional().describe('Keep the full provider response body in each message\'s metadata for auditing'),
+  logLevel: z.enum(['debug', 'info', 'warn', 'error', 'silent']).optional().describe('Lowest level of diagnostics written to stderr (default info)'),
+  verbose: z.boolean().optional().describe('Log debug diagnostics regardless of logLevel'),
+  requestsPerMinute: z.number().int().min(0).optional().describe('Requests per minute allowed per provider account, shared by models with the same provider and API key (0 for no limit)'),
+  showThinking: z.boolean().optional().describe('Include each model\'s extended thinking in discussion output'),
+  maxContinuations: z.number().int().min(0).optional().describe('Follow-up requests allowed to finish a reply cut off at the token limit (0 disables)'),
+  summarizeOnEnd: z.boolean().optional().describe('Generate a final consensus summary when the discussion ends'),
+  summarizer: z.string().optional().describe('Name or provider ID of the model that writes the final summary (defaults to the host)'),
+  moderator: z.string().optional().describe('Name or provider ID of a model that picks who speaks each round ("host" for the host)'),
+  turnMode: z.enum(['sequential', 'parallel']).optional().describe('sequential: participants reply one at a time and see earlier replies; parallel: all reply at once from the same context'),
+  mentionMode: z.boolean().optional().describe('Only let participants reply when the host (or the topic, in the first round) @mentions them'),
+  lateJoinPrimer: z.string().optional().describe('Primer for models that join mid-discussion; {topic}, {participants} and {history} are filled in'),
+  maxSpeakersPerRound: z.number().int().min(0).optional().describe('Maximum participants replying after the host each round, preferring those the host @mentions (0 for no limit)'),
+  liveDisplay: z.boolean().optional().describe('Draw replies on stderr as they stream in; for running the council from a terminal script, not inside the OpenCode TUI'),
 })
 
+/**
+ * One model's settings
+ */
+export type ModelInput = {
+  providerId: string
+  modelId?: string
+  name?: string
+  apiKey?: string
+  baseURL?: string
+  isHost?: boolean
+  firstTurnPrompt?: string
+  maxTokens?: number
+  temperature?: number
+  topP?: number
+  timeout?: number
+  systemPrompt?: string
+  includeSystemMessages?: boolean
+  maxContextMessages?: number
+  maxContextTokens?: number
+  responseSchema?: JsonSchema
+  strictSchema?: boolean
+  pricing?: ModelPricing
+  deployment?: string
+  apiVersion?: string
+  proxy?: string
+  headers?: Record<string, string>
+  overrideHeaders?: boolean
+}
+
+/**
+ * Where setup looks up API keys not given in the input
+ */
+export type SetupContext = {
+  getApiKey?: (providerId: string) => string | undefined
+}
+
 export type SetupInput = {
-  models: Array<{
-    providerId: string
-    modelId?: string
-    name?: string
-    apiKey?: string
-    baseURL?: string
-    isHost?: boolean
-  }>
+  models: ModelInput[]
   maxRounds?: number
+  responseTimeout?: number
   locale?: 'en' | 'zh' | 'zh-TW' | 'ja' | 'ko'
+  announceMembership?: boolean
+  duplicateReplyWindow?: number
+  minResponseLength?: number
+  onAllParticipantsFailed?: 'continue' | 'end'
+  keepRawResponses?: boolean
+  logLevel?: LogLevel
+  verbose?: boolean
+  requestsPerMinute?: number
+  showThinking?: boolean
+  maxSpeakersPerRound?: number
+  lateJoinPrimer?: string
+  mentionMode?: boolean
+  turnMode?: 'sequential' | 'parallel'
+  maxContinuations?: number
+  summarizeOnEnd?: boolean
+  summarizer?: string
+  moderator?: string
+  liveDisplay?: boolean
 }
 
 /**
@@ -51,6 +146,241 @@ export interface SetupOutput {
     name: string
     isHost: boolean
   }>
+  /** Settings that were accepted but will have no effect */
+  warnings?: string[]
+}
+
+/**
+ * Whether a string is a well-formed http(s) URL
+ */
+function isHttpURL(value: string): boolean {
+  try {
+    const { protocol } = new URL(value)
+    return protocol === 'http:' || protocol === 'https:'
+  } catch {
+    return false
+  }
+}
+
+/**
+ * Validate one model's settings that the schema alone may not enforce
+ *
+ * @returns a description of the problem found, or null
+ */
+export function validateModelInput(model: ModelInput): string | null {
+  const label = model.name ?? model.providerId
+
+  if (model.maxTokens !== undefined && (!Number.isInteger(model.maxTokens) || model.maxTokens <= 0)) {
+    return `maxTokens for ${label} must be a positive integer, got ${model.maxTokens}`
+  }
+  if (model.timeout !== undefined && !(model.timeout > 0)) {
+    return `timeout for ${label} must be a positive number of milliseconds, got ${model.timeout}`
+  }
+  if (model.temperature !== undefined && !(model.temperature >= 0 && model.temperature <= 2)) {
+    return `temperature for ${label} must be between 0 and 2, got ${model.temperature}`
+  }
+  if (model.topP !== undefined && !(model.topP >= 0 && model.topP <= 1)) {
+    return `topP for ${label} must be between 0 and 1, got ${model.topP}`
+  }
+  if (model.responseSchema !== undefined) {
+    const error = jsonSchemaError(model.responseSchema)
+    if (error) return `responseSchema for ${label} is not a usable JSON schema: ${error}`
+  }
+  if (model.proxy !== undefined && !isHttpURL(model.proxy)) {
+    return `proxy for ${label} must be an http:// or https:// URL, got ${model.proxy}; SOCKS proxies are not supported`
+  }
+  if (model.providerId === 'azure' && !model.baseURL) {
+    return `baseURL for ${label} must be the Azure OpenAI resource URL, e.g. https://my-resource.openai.azure.com`
+  }
+
+  return null
+}
+
+/**
+ * Model settings that only reach the provider on direct API calls.
+ * Requests through the OpenCode client carry just the model and prompt,
+ * so OpenCode's own settings for the model apply instead.
+ */
+export const DIRECT_API_ONLY_OPTIONS: Array<keyof ModelInput> = [
+  'maxTokens',
+  'temperature',
+  'topP',
+  'proxy',
+  'headers',
+  'overrideHeaders',
+]
+
+/**
+ * Council settings that only take effect on direct API calls. Replies
+ * through the OpenCode client carry no finish reason, raw body or thinking.
+ */
+export const DIRECT_API_ONLY_SETUP_OPTIONS: Array<keyof SetupInput> = [
+  'maxContinuations',
+  'keepRawResponses',
+  'showThinking',
+]
+
+/**
+ * Warn about council settings that the OpenCode client will ignore
+ *
+ * @returns one warning per setting that is turned on; empty on direct API calls
+ */
+export function clientIgnoredSetupWarnings(input: SetupInput): string[] {
+  if (!providerAdapter.hasClient) return []
+
+  // 0 and false leave these features off, so there's nothing to ignore
+  const warnings = DIRECT_API_ONLY_SETUP_OPTIONS
+    .filter(key => input[key])
+    .map(key => `${key} only applies to direct API calls and is ignored through the OpenCode client`)
+  for (const warning of warnings) logger.warn(warning)
+  return warnings
+}
+
+/**
+ * Warn about a model's settings that the OpenCode client will ignore
+ *
+ * @returns one warning per ignored setting; empty on direct API calls
+ */
+export function clientIgnoredWarnings(model: ModelInput): string[] {
+  if (!providerAdapter.hasClient) return []
+
+  const label = model.name ?? model.providerId
+  const warnings = DIRECT_API_ONLY_OPTIONS
+    .filter(key => model[key] !== undefined)
+    .map(key => `${key} for ${label} only applies to direct API calls and is ignored through the OpenCode client`)
+  for (const warning of warnings) logger.warn(warning)
+  return warnings
+}
+
+/**
+ * Validate the setup input and each model's settings
+ *
+ * @returns a description of the first problem found, or null
+ */
+export function validateSetupInput(input: SetupInput): string | null {
+  if (!input.models?.length) {
+    return t('setup.noModelsHint')
+  }
+  if (input.models.length < 2) {
+    return t('setup.minModelsRequired')
+  }
+  if (input.responseTimeout !== undefined && !(input.responseTimeout > 0)) {
+    return `responseTimeout must be a positive number of milliseconds, got ${input.responseTimeout}`
+  }
+
+  for (const model of input.models) {
+    const invalid = validateModelInput(model)
+    if (invalid) return invalid
+  }
+
+  if (input.summarizer && !input.models.some(m => modelAnswersTo(m, input.summarizer!))) {
+    return t('errors.modelNotFound', { model: input.summarizer })
+  }
+  if (
+    input.moderator &&
+    input.moderator.toLowerCase() !== 'host' &&
+    !input.models.some(m => modelAnswersTo(m, input.moderator!))
+  ) {
+    return t('errors.modelNotFound', { model: input.moderator })
+  }
+
+  return null
+}
+
+/**
+ * Whether a model in the setup input goes by a name or provider ID,
+ * compared case-insensitively as the council looks them up
+ */
+function modelAnswersTo(model: ModelInput, key: string): boolean {
+  const wanted = key.toLowerCase()
+  return model.providerId.toLowerCase() === wanted || buildProviderConfig(model).name.toLowerCase() === wanted
+}
+
+/**
+ * Resolve a model's settings to a provider config, filling in the preset
+ * for predefined providers and the configured API key when none is given
+ */
+export function buildProviderConfig(modelConfig: ModelInput, context: SetupContext = {}): ProviderConfig {
+  // Get provider config
+  let providerConfig: ProviderConfig
+
+  // Check if it's a predefined provider
+  const predefinedFactory = PREDEFINED_PROVIDERS[modelConfig.providerId as keyof typeof PREDEFINED_PROVIDERS]
+  
+  if (predefinedFactory) {
+    const apiKey = modelConfig.apiKey ?? context.getApiKey?.(modelConfig.providerId) ?? ''
+    providerConfig = predefinedFactory(apiKey)
+    
+    // Override model ID if specified
+    if (modelConfig.modelId) {
+      providerConfig.modelId = modelConfig.modelId
+    }
+  } else {
+    // Custom provider
+    providerConfig = createProviderConfig(
+      modelConfig.providerId,
+      modelConfig.name ?? modelConfig.providerId,
+      modelConfig.baseURL ?? '',
+      modelConfig.apiKey ?? context.getApiKey?.(modelConfig.providerId) ?? '',
+      modelConfig.modelId ?? ''
+    )
+  }
+
+  // Override name if specified
+  if (modelConfig.name) {
+    providerConfig.name = modelConfig.name
+  }
+
+  if (modelConfig.maxTokens !== undefined) {
+    providerConfig.maxTokens = modelConfig.maxTokens
+  }
+  if (modelConfig.temperature !== undefined) {
+    providerConfig.temperature = modelConfig.temperature
+  }
+  if (modelConfig.topP !== undefined) {
+    providerConfig.topP = modelConfig.topP
+  }
+  if (modelConfig.timeout !== undefined) {
+    providerConfig.timeout = modelConfig.timeout
+  }
+  if (modelConfig.systemPrompt) {
+    providerConfig.systemPrompt = modelConfig.systemPrompt
+  }
+  if (modelConfig.includeSystemMessages !== undefined) {
+    providerConfig.includeSystemMessages = modelConfig.includeSystemMessages
+  }
+  if (modelConfig.maxContextMessages !== undefined) {
+    providerConfig.maxContextMessages = modelConfig.maxContextMessages
+  }
+  if (modelConfig.maxContextTokens !== undefined) {
+    providerConfig.maxContextTokens = modelConfig.maxContextTokens
+  }
+  if (modelConfig.responseSchema) {
+    providerConfig.responseSchema = modelConfig.responseSchema
+  }
+  if (modelConfig.strictSchema !== undefined) {
+    providerConfig.strictSchema = modelConfig.strictSchema
+  }
+  if (modelConfig.pricing) {
+    providerConfig.pricing = modelConfig.pricing
+  }
+  if (modelConfig.deployment) {
+    providerConfig.deployment = modelConfig.deployment
+  }
+  if (modelConfig.apiVersion) {
+    providerConfig.apiVersion = modelConfig.apiVersion
+  }
+  if (modelConfig.proxy) {
+    providerConfig.proxy = modelConfig.proxy
+  }
+  if (modelConfig.headers) {
+    providerConfig.headers = modelConfig.headers
+  }
+  if (modelConfig.overrideHeaders !== undefined) {
+    providerConfig.overrideHeaders = modelConfig.overrideHeaders
+  }
+
+  return providerConfig
 }
 
 /**
@@ -58,52 +388,54 @@ export interface SetupOutput {
  */
 export async function executeSetup(
   input: SetupInput,
-  context: {
-    getApiKey?: (providerId: string) => string | undefined
-  } = {}
+  context: SetupContext = {}
 ): Promise<SetupOutput> {
+  const invalid = validateSetupInput(input)
+  if (invalid) {
+    return {
+      success: false,
+      message: t('errors.invalidConfig', { message: invalid }),
+      councilId: '',
+      participants: [],
+    }
+  }
+
   // Reset any existing council
   resetCouncil()
 
   // Create new council with config (use defaults if not provided)
   const council = getCouncil({
     maxRounds: input.maxRounds ?? 5,
+    responseTimeout: input.responseTimeout,
     locale: input.locale ?? 'en',
+    announceMembership: input.announceMembership,
+    duplicateReplyWindow: input.duplicateReplyWindow,
+    minResponseLength: input.minResponseLength,
+    onAllParticipantsFailed: input.onAllParticipantsFailed,
+    keepRawResponses: input.keepRawResponses,
+    logLevel: input.logLevel,
+    verbose: input.verbose,
+    requestsPerMinute: input.requestsPerMinute,
+    showThinking: input.showThinking,
+    maxSpeakersPerRound: input.maxSpeakersPerRound,
+    lateJoinPrimer: input.lateJoinPrimer,
+    mentionMode: input.mentionMode,
+    turnMode: input.turnMode,
+    maxContinuations: input.maxContinuations,
+    summarizeOnEnd: input.summarizeOnEnd,
+    summarizer: input.summarizer,
   })
 
+  if (input.liveDisplay) {
+    attachStreamingDisplay(council, createTerminalRenderer())
+  }
+
   const participants: SetupOutput['participants'] = []
+  const warnings = [...clientIgnoredSetupWarnings(input), ...input.models.flatMap(clientIgnoredWarnings)]
   let hostSet = false
 
   for (const modelConfig of input.models) {
-    // Get provider config
-    let providerConfig: ProviderConfig
-
-    // Check if it's a predefined provider
-    const predefinedFactory = PREDEFINED_PROVIDERS[modelConfig.providerId as keyof typeof PREDEFINED_PROVIDERS]
-    
-    if (predefinedFactory) {
-      const apiKey = modelConfig.apiKey ?? context.getApiKey?.(modelConfig.providerId) ?? ''
-      providerConfig = predefinedFactory(apiKey)
-      
-      // Override model ID if specified
-      if (modelConfig.modelId) {
-        providerConfig.modelId = modelConfig.modelId
-      }
-    } else {
-      // Custom provider
-      providerConfig = createProviderConfig(
-        modelConfig.providerId,
-        modelConfig.name ?? modelConfig.providerId,
-        modelConfig.baseURL ?? '',
-        modelConfig.apiKey ?? context.getApiKey?.(modelConfig.providerId) ?? '',
-        modelConfig.modelId ?? ''
-      )
-    }
-
-    // Override name if specified
-    if (modelConfig.name) {
-      providerConfig.name = modelConfig.name
-    }
+    const providerConfig = buildProviderConfig(modelConfig, context)
 
     // Add participant
     // If user explicitly set isHost on any model, respect that
@@ -122,6 +454,7 @@ export async function executeSetup(
     const participant = council.addParticipant(providerConfig, {
       isHost,
       name: modelConfig.name,
+      firstTurnPrompt: modelConfig.firstTurnPrompt,
     })
 
     if (isHost) {
@@ -135,11 +468,28 @@ export async function executeSetup(
     })
   }
 
+  if (input.moderator) {
+    const key = input.moderator.toLowerCase()
+    const moderator = council.participants.find(p =>
+      key === 'host' ? p.isHost : p.name.toLowerCase() === key || p.provider.id.toLowerCase() === key
+    )
+    if (!moderator) {
+      return {
+        success: false,
+        message: t('errors.modelNotFound', { model: input.moderator }),
+        councilId: council.discussionId,
+        participants,
+      }
+    }
+    council.setCoordinator(createModerator(moderator, input.maxSpeakersPerRound || undefined))
+  }
+
   return {
     success: true,
     message: t('setup.ready'),
     councilId: council.discussionId,
     participants,
+    ...(warnings.length > 0 && { warnings }),
   }
 }
 
@@ -103,6 +103,82 @@ describe('executeStatus', () => {
     expect(result.topic).toBe('No host selected')
   })
 
+  it('should report message counts and last activity per participant', async () => {
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      participants: [
+        { id: 'h1', name: 'Host Model', status: 'idle', isHost: true },
+        { id: 'p1', name: 'Participant 1', status: 'idle', isHost: false },
+      ],
+      rounds: [
+        {
+          number: 1,
+          messages: [
+            { round: 1, from: 'System', content: 'Round 1', type: 'system', timestamp: new Date('2024-01-15T10:00:00Z') },
+            { round: 1, from: 'Host Model', content: 'Hello', type: 'assistant', metadata: { participantId: 'h1' }, timestamp: new Date('2024-01-15T10:01:00Z') },
+            { round: 1, from: 'Participant 1', content: 'Hi', type: 'assistant', metadata: { participantId: 'p1' }, timestamp: new Date('2024-01-15T10:02:00Z') },
+          ],
+        },
+        {
+          number: 2,
+          messages: [
+            { round: 2, from: 'Host Model', content: 'Again', type: 'assistant', metadata: { participantId: 'h1' }, timestamp: new Date('2024-01-15T10:05:00Z') },
+          ],
+        },
+      ],
+    })
+
+    const result = await executeStatus({})
+
+    expect(result.participants[0]).toMatchObject({ messageCount: 2, lastActiveAt: '2024-01-15T10:05:00.000Z' })
+    expect(result.participants[1]).toMatchObject({ messageCount: 1, lastActiveAt: '2024-01-15T10:02:00.000Z' })
+    expect(result.lastActivity).toBe('2024-01-15T10:05:00.000Z')
+  })
+
+  it('should report whether the host is active', async () => {
+    const host = { id: 'h1', name: 'Host Model', isHost: true }
+    const reply = { round: 2, from: 'Host Model', content: 'Again', type: 'assistant', metadata: { participantId: 'h1' }, timestamp: new Date('2024-01-15T10:05:00Z') }
+
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      participants: [{ ...host, status: 'idle' }],
+    })
+    expect((await executeStatus({})).hostActive).toBe(false)
+
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      participants: [{ ...host, status: 'thinking' }],
+    })
+    expect((await executeStatus({})).hostActive).toBe(true)
+
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      participants: [{ ...host, status: 'idle' }],
+      rounds: [{ number: 2, messages: [reply] }],
+    })
+    expect((await executeStatus({})).hostActive).toBe(true)
+
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      status: 'completed',
+      participants: [{ ...host, status: 'idle' }],
+      rounds: [{ number: 2, messages: [reply] }],
+    })
+    expect((await executeStatus({})).hostActive).toBe(false)
+  })
+
+  it('should report no activity before the discussion starts', async () => {
+    vi.mocked(mockCouncil.getState).mockReturnValue({
+      ...mockState,
+      rounds: [],
+    })
+
+    const result = await executeStatus({})
+
+    expect(result.participants[0]).toMatchObject({ messageCount: 0, lastActiveAt: null })
+    expect(result.lastActivity).toBeNull()
+  })
+
   it('should format timestamps as ISO strings', async () => {
     const result = await executeStatus({ includeMessages: true })
 
@@ -7,6 +7,7 @@
 import { z } from 'zod'
 import { getCouncil } from '../core/council'
 import { t } from '../i18n'
+import type { Message, Participant } from '../types'
 
 /**
  * Status tool input schema
@@ -36,7 +37,18 @@ export interface StatusOutput {
     name: string
     status: string
     isHost: boolean
+    /** Number of replies from this participant across all rounds */
+    messageCount: number
+    /** Timestamp of the participant's latest reply */
+    lastActiveAt: string | null
   }>
+  /** Timestamp of the latest message in the discussion */
+  lastActivity: string | null
+  /**
+   * Whether the host is taking part in the running discussion: working on
+   * a reply now, or has replied in the current round
+   */
+  hostActive: boolean
   messages?: Array<{
     round: number
     from: string
@@ -51,6 +63,22 @@ export interface StatusOutput {
 export async function executeStatus(input: StatusInput): Promise<StatusOutput> {
   const council = getCouncil()
   const state = council.getState()
+  const allMessages = state.rounds.flatMap(round => round.messages)
+
+  const latest = (messages: Message[]) =>
+    messages.reduce<Date | null>(
+      (last, m) => (!last || m.timestamp > last ? m.timestamp : last),
+      null
+    )?.toISOString() ?? null
+
+  const isFrom = (m: Message, p: Participant) =>
+    m.type !== 'system' &&
+    (m.metadata?.participantId ? m.metadata.participantId === p.id : m.from === p.name)
+
+  const hostParticipant = state.participants.find(p => p.isHost)
+  const hostBusy = hostParticipant?.status === 'thinking' || hostParticipant?.status === 'responding'
+  const hostSpokeThisRound = !!hostParticipant &&
+    allMessages.some(m => m.round === state.currentRound && isFrom(m, hostParticipant))
 
   const output: StatusOutput = {
     councilId: state.id,
@@ -62,22 +90,27 @@ export async function executeStatus(input: StatusInput): Promise<StatusOutput> {
       name: state.host.name,
       status: state.host.status,
     } : null,
-    participants: state.participants.map(p => ({
-      name: p.name,
-      status: p.status,
-      isHost: p.isHost,
-    })),
+    participants: state.participants.map(p => {
+      const replies = allMessages.filter(m => isFrom(m, p))
+      return {
+        name: p.name,
+        status: p.status,
+        isHost: p.isHost,
+        messageCount: replies.length,
+        lastActiveAt: latest(replies),
+      }
+    }),
+    lastActivity: latest(allMessages),
+    hostActive: state.status === 'running' && (hostBusy || hostSpokeThisRound),
   }
 
   if (input.includeMessages) {
-    output.messages = state.rounds.flatMap(round =>
-      round.messages.map(m => ({
-        round: m.round,
-        from: m.from,
-        content: m.content,
-        timestamp: m.timestamp.toISOString(),
-      }))
-    )
+    output.messages = allMessages.map(m => ({
+      round: m.round,
+      from: m.from,
+      content: m.content,
+      timestamp: m.timestamp.toISOString(),
+    }))
   }
 
   return output
@@ -9,6 +9,11 @@
  */
 export type Locale = 'en' | 'zh' | 'zh-TW' | 'ja' | 'ko'
 
+/**
+ * Diagnostic log levels, from most to least verbose
+ */
+export type LogLevel = 'debug' | 'info' | 'warn' | 'error' | 'silent'
+
 /**
  * Model provider configuration
  */
@@ -23,6 +28,69 @@ export interface ProviderConfig {
   apiKey: string
   /** Model ID to use */
   modelId: string
+  /** Maximum tokens to generate on direct API calls (provider default when unset) */
+  maxTokens?: number
+  /** Sampling temperature, 0-2, on direct API calls (provider default when unset) */
+  temperature?: number
+  /** Nucleus sampling probability, 0-1, on direct API calls (provider default when unset) */
+  topP?: number
+  /** Response timeout for this model in ms (overrides the scaled council default) */
+  timeout?: number
+  /** Persona or role instructions appended to this model's system prompt */
+  systemPrompt?: string
+  /** Whether system notices appear in this model's discussion context (default true) */
+  includeSystemMessages?: boolean
+  /** Most recent messages included in this model's context (default 10) */
+  maxContextMessages?: number
+  /** Rough token budget for this model's context; older messages are dropped to fit */
+  maxContextTokens?: number
+  /** JSON schema replies must conform to; requests structured output where supported */
+  responseSchema?: JsonSchema
+  /**
+   * Send the schema with `strict: true` on OpenAI-compatible calls. The
+   * schema must then follow OpenAI's strict-mode rules, e.g. every property
+   * required and `additionalProperties: false`.
+   */
+  strictSchema?: boolean
+  /** Price in USD per million tokens, used to estimate the discussion's cost */
+  pricing?: ModelPricing
+  /** Azure OpenAI deployment name (defaults to the model ID) */
+  deployment?: string
+  /** Azure OpenAI REST API version */
+  apiVersion?: string
+  /** HTTP(S) proxy URL for this model's direct API calls (HTTPS_PROXY applies when unset) */
+  proxy?: string
+  /**
+   * Extra headers sent with every request, after the built-in ones.
+   * Headers that clash with a built-in (e.g. Authorization) are ignored
+   * unless `overrideHeaders` is set.
+   */
+  headers?: Record<string, string>
+  /** Let `headers` replace built-in headers such as the auth header */
+  overrideHeaders?: boolean
+}
+
+/**
+ * Per-model token prices in USD per million tokens
+ */
+export interface ModelPricing {
+  input: number
+  output: number
+}
+
+/**
+ * JSON schema subset used for structured replies
+ *
+ * Other keywords (e.g. `description`, `additionalProperties`) are sent to
+ * the provider but not checked locally.
+ */
+export interface JsonSchema {
+  type?: 'object' | 'array' | 'string' | 'number' | 'integer' | 'boolean' | 'null'
+  properties?: Record<string, JsonSchema>
+  required?: string[]
+  items?: JsonSchema
+  enum?: unknown[]
+  [keyword: string]: unknown
 }
 
 /**
@@ -39,6 +107,20 @@ export interface Participant {
   isHost: boolean
   /** Participant status */
   status: ParticipantStatus
+  /** Instruction for an introduction posted once before the participant's first round */
+  firstTurnPrompt?: string
+}
+
+/**
+ * Options for adding a participant
+ */
+export interface ParticipantOptions {
+  /** Whether this participant is the host */
+  isHost?: boolean
+  /** Display name (defaults to the provider name) */
+  name?: string
+  /** Instruction for an introduction posted once before the participant's first round */
+  firstTurnPrompt?: string
 }
 
 /**
@@ -62,10 +144,35 @@ export interface Message {
   timestamp: Date
   /** Message type */
   type: MessageType
-  /** Optional metadata */
+  /** Optional metadata; replies carry ReplyMetadata */
   metadata?: Record<string, unknown>
 }
 
+/**
+ * Metadata recorded on each participant reply
+ */
+export type ReplyMetadata = {
+  participantId: string
+  isHost: boolean
+  /** Provider ID the reply came from */
+  provider: string
+  /** Model ID the reply came from */
+  model: string
+  /** Time from the first request to the finished reply, including retries and continuations */
+  latencyMs: number
+  /** Tokens the provider reported, when it did */
+  usage?: { inputTokens?: number; outputTokens?: number; totalTokens?: number }
+  /** Extended thinking returned with the reply */
+  thinking?: string
+  /** ID of the message in the round that @mentioned the participant, which this reply answers */
+  replyTo?: string
+  /**
+   * Full provider response body, when keepRawResponses is set; an array of
+   * bodies in order when the reply was stitched from continuations
+   */
+  rawResponse?: unknown
+}
+
 /**
  * Message type
  */
@@ -127,7 +234,7 @@ export type DiscussionStatus = 'idle' | 'setup' | 'running' | 'paused' | 'comple
  * Discussion configuration
  */
 export interface DiscussionConfig {
-  /** Maximum number of rounds */
+  /** Maximum number of rounds (0 for unlimited) */
   maxRounds: number
   /** Timeout per response (ms) */
   responseTimeout: number
@@ -135,6 +242,49 @@ export interface DiscussionConfig {
   autoSummarize: boolean
   /** Locale for messages */
   locale: Locale
+  /** Lowest level of diagnostics written to stderr */
+  logLevel: LogLevel
+  /** Log at debug level regardless of logLevel */
+  verbose: boolean
+  /**
+   * Requests per minute allowed per provider account (0 for no limit);
+   * models sharing a provider and API key share the budget
+   */
+  requestsPerMinute: number
+  /** Whether to post a system message when participants join or leave mid-discussion */
+  announceMembership: boolean
+  /** Number of a participant's own previous replies checked for verbatim repeats (0 disables) */
+  duplicateReplyWindow: number
+  /** Replies shorter than this (trimmed characters) are retried once with a nudge (0 disables) */
+  minResponseLength: number
+  /** What to do after a round in which every non-host participant failed */
+  onAllParticipantsFailed: 'continue' | 'end'
+  /** Whether to keep the full provider response body in message metadata */
+  keepRawResponses: boolean
+  /** Whether discussion output includes each reply's extended thinking */
+  showThinking: boolean
+  /** Maximum participants replying after the host each round (0 for no limit) */
+  maxSpeakersPerRound: number
+  /**
+   * Primer prepended to the first prompt of a participant who joins
+   * mid-discussion; `{topic}`, `{participants}` and `{history}` are filled
+   * in. Empty uses the built-in primer.
+   */
+  lateJoinPrimer: string
+  /** Only participants @mentioned by the host (or the topic) reply each round */
+  mentionMode: boolean
+  /**
+   * How participants take their turns after the host: 'sequential' asks
+   * them one at a time so each sees the replies before it, 'parallel'
+   * asks them all at once from the same context
+   */
+  turnMode: 'sequential' | 'parallel'
+  /** Follow-up requests allowed to finish a reply cut off at the token limit (0 disables) */
+  maxContinuations: number
+  /** Whether to generate a final consensus summary when the discussion ends */
+  summarizeOnEnd: boolean
+  /** Name or provider ID of the participant that writes the final summary (empty for the host) */
+  summarizer: string
 }
 
 /**
@@ -145,6 +295,22 @@ export const DEFAULT_CONFIG: DiscussionConfig = {
   responseTimeout: 120000, // 2 minutes
   autoSummarize: false,
   locale: 'en',
+  logLevel: 'info',
+  verbose: false,
+  requestsPerMinute: 0,
+  announceMembership: true,
+  duplicateReplyWindow: 0,
+  minResponseLength: 0,
+  onAllParticipantsFailed: 'continue',
+  keepRawResponses: false,
+  showThinking: false,
+  maxSpeakersPerRound: 0,
+  lateJoinPrimer: '',
+  mentionMode: false,
+  turnMode: 'sequential',
+  maxContinuations: 0,
+  summarizeOnEnd: false,
+  summarizer: '',
 }
 
 /**
@@ -177,6 +343,43 @@ export interface CouncilEvent<T = unknown> {
  */
 export type EventHandler<T = unknown> = (event: CouncilEvent<T>) => void | Promise<void>
 
+/**
+ * Input given to a coordinator when choosing who speaks in a round
+ */
+export interface CoordinatorContext {
+  /** Discussion topic */
+  topic: string
+  /** Round about to run */
+  round: number
+  /** Most recent message in the discussion, if any */
+  latestMessage: Message | null
+  /** Non-host participants eligible to speak */
+  candidates: Participant[]
+  /**
+   * Ask a model through the council, so the call gets the usual response
+   * timeout and counts towards usage; resolves to the reply text
+   */
+  ask: (participant: Participant, prompt: string) => Promise<string>
+}
+
+/**
+ * Chooses which non-host participants respond in a round
+ */
+export type Coordinator = (context: CoordinatorContext) => Participant[] | Promise<Participant[]>
+
+/**
+ * A request the council would send, as shown by a dry run
+ */
+export interface RequestPreview {
+  participantId: string
+  name: string
+  isHost: boolean
+  /** Whether this is the participant's introduction rather than its round turn */
+  intro: boolean
+  systemPrompt: string
+  prompt: string
+}
+
 /**
  * Model response
  */
@@ -189,6 +392,30 @@ export interface ModelResponse {
   finishReason?: string
 }
 
+/**
+ * Tokens used by one participant, or by the whole council
+ */
+export interface UsageTotals {
+  inputTokens: number
+  outputTokens: number
+  /** Model calls made */
+  calls: number
+  /** Estimated cost in USD, when pricing is known for every model counted */
+  estimatedCost?: number
+}
+
+/**
+ * Token usage of a discussion
+ */
+export interface UsageReport {
+  participants: Array<UsageTotals & {
+    participantId: string
+    name: string
+    modelId: string
+  }>
+  total: UsageTotals
+}
+
 /**
  * Council callbacks for UI updates
  */
@@ -201,6 +428,8 @@ export interface CouncilCallbacks {
   onThinking?: (participant: Participant) => void
   /** Called when a participant responds */
   onResponse?: (participant: Participant, content: string) => void
+  /** Called with each streamed text delta of a participant's reply */
+  onDelta?: (participant: Participant, delta: string) => void
   /** Called when an error occurs */
   onError?: (error: Error, participant?: Participant) => void
   /** Called when a round completes */
@@ -1,15 +1,22 @@
 import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest'
 import {
   generateId,
+  setIdGenerator,
+  hashString,
   sleep,
   timeout,
   retry,
   formatDate,
   truncate,
+  redactSecrets,
+  validateJsonSchema,
+  jsonSchemaError,
   deepClone,
   isObject,
   deepMerge,
   createEventEmitter,
+  logger,
+  setLogLevel,
 } from './index'
 
 describe('generateId', () => {
@@ -30,6 +37,43 @@ describe('generateId', () => {
   })
 })
 
+describe('setIdGenerator', () => {
+  afterEach(() => {
+    setIdGenerator(null)
+  })
+
+  it('should use the injected generator', () => {
+    let next = 0
+    setIdGenerator(() => `id-${++next}`)
+
+    expect(generateId()).toBe('id-1')
+    expect(generateId()).toBe('id-2')
+  })
+
+  it('should restore the default generator', () => {
+    setIdGenerator(() => 'fixed')
+    setIdGenerator(null)
+
+    expect(generateId()).not.toBe('fixed')
+    expect(generateId().split('-')).toHaveLength(2)
+  })
+})
+
+describe('hashString', () => {
+  it('should return the same hash for the same input', () => {
+    expect(hashString('hello world')).toBe(hashString('hello world'))
+  })
+
+  it('should return different hashes for different input', () => {
+    expect(hashString('hello world')).not.toBe(hashString('hello world!'))
+  })
+
+  it('should return an 8-character hex string', () => {
+    expect(hashString('')).toMatch(/^[0-9a-f]{8}$/)
+    expect(hashString('some longer content')).toMatch(/^[0-9a-f]{8}$/)
+  })
+})
+
 describe('sleep', () => {
   it('should resolve after specified duration', async () => {
     const start = Date.now()
@@ -81,6 +125,27 @@ describe('retry', () => {
     await expect(retry(fn, { maxRetries: 2, initialDelay: 10 })).rejects.toThrow('always fails')
     expect(fn).toHaveBeenCalledTimes(3) // initial + 2 retries
   })
+
+  it('should let delayFor adjust the wait per error', async () => {
+    const fn = vi.fn()
+      .mockRejectedValueOnce(new Error('busy'))
+      .mockResolvedValue('success')
+    const delayFor = vi.fn(() => 0)
+
+    const result = await retry(fn, { maxRetries: 1, initialDelay: 10_000, delayFor })
+
+    expect(result).toBe('success')
+    expect(delayFor).toHaveBeenCalledWith(expect.objectContaining({ message: 'busy' }), 10_000)
+  })
+
+  it('should stop retrying when shouldRetry rejects the error', async () => {
+    const fn = vi.fn().mockRejectedValue(new Error('bad key'))
+
+    await expect(
+      retry(fn, { maxRetries: 3, initialDelay: 10, shouldRetry: () => false })
+    ).rejects.toThrow('bad key')
+    expect(fn).toHaveBeenCalledTimes(1)
+  })
 })
 
 describe('formatDate', () => {
@@ -108,6 +173,64 @@ describe('truncate', () => {
   })
 })
 
+describe('redactSecrets', () => {
+  it('should redact known secrets', () => {
+    const text = 'request failed for key my-secret-key-123'
+    expect(redactSecrets(text, ['my-secret-key-123'])).toBe('request failed for key [REDACTED]')
+  })
+
+  it('should redact common key patterns', () => {
+    expect(redactSecrets('key sk-abcdefghijklmnop rejected')).toBe('key [REDACTED] rejected')
+    expect(redactSecrets('Authorization: Bearer abcdefghijk123')).toBe('Authorization: Bearer [REDACTED]')
+  })
+
+  it('should ignore empty and very short secrets', () => {
+    expect(redactSecrets('a short message', ['', undefined, 'a'])).toBe('a short message')
+  })
+})
+
+describe('validateJsonSchema', () => {
+  const schema = {
+    type: 'object' as const,
+    properties: {
+      verdict: { type: 'string' as const, enum: ['yes', 'no'] },
+      points: { type: 'array' as const, items: { type: 'integer' as const } },
+    },
+    required: ['verdict'],
+  }
+
+  it('should accept a conforming value', () => {
+    expect(validateJsonSchema({ verdict: 'yes', points: [1, 2] }, schema)).toBeNull()
+  })
+
+  it('should report the first mismatch with its path', () => {
+    expect(validateJsonSchema({}, schema)).toBe('$.verdict is required')
+    expect(validateJsonSchema({ verdict: 'maybe' }, schema)).toBe('$.verdict must be one of ["yes","no"]')
+    expect(validateJsonSchema({ verdict: 'no', points: [1, 1.5] }, schema)).toBe('$.points[1] must be an integer')
+    expect(validateJsonSchema([], schema)).toBe('$ must be an object')
+  })
+})
+
+describe('jsonSchemaError', () => {
+  it('should accept the supported keywords and ignore others', () => {
+    expect(jsonSchemaError({
+      type: 'object',
+      description: 'A verdict',
+      properties: { verdict: { type: 'string', enum: ['yes', 'no'] }, tags: { type: 'array', items: { type: 'string' } } },
+      required: ['verdict'],
+    })).toBeNull()
+  })
+
+  it('should point at the first malformed keyword', () => {
+    expect(jsonSchemaError('object')).toBe('$ must be a schema object')
+    expect(jsonSchemaError({ type: 'map' })).toContain('$.type must be one of')
+    expect(jsonSchemaError({ properties: { a: { type: 'text' } } })).toContain('$.properties.a.type must be one of')
+    expect(jsonSchemaError({ required: 'verdict' })).toBe('$.required must be an array of property names')
+    expect(jsonSchemaError({ items: [] })).toBe('$.items must be a schema object')
+    expect(jsonSchemaError({ enum: 'yes' })).toBe('$.enum must be an array')
+  })
+})
+
 describe('deepClone', () => {
   it('should create a deep copy of an object', () => {
     const original = { a: 1, b: { c: 2 } }
@@ -188,6 +311,17 @@ describe('createEventEmitter', () => {
     expect(handler2).toHaveBeenCalled()
   })
 
+  it('should count listeners', () => {
+    const emitter = createEventEmitter<{ test: [] }>()
+    expect(emitter.listenerCount('test')).toBe(0)
+
+    const unsubscribe = emitter.on('test', vi.fn())
+    expect(emitter.listenerCount('test')).toBe(1)
+
+    unsubscribe()
+    expect(emitter.listenerCount('test')).toBe(0)
+  })
+
   it('should unsubscribe from events', () => {
     const emitter = createEventEmitter<{ test: [] }>()
     const handler = vi.fn()
@@ -244,3 +378,51 @@ describe('createEventEmitter', () => {
     consoleSpy.mockRestore()
   })
 })
+
+describe('logger', () => {
+  afterEach(() => {
+    setLogLevel('info')
+    vi.restoreAllMocks()
+  })
+
+  it('should drop lines below the log level', () => {
+    const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+
+    logger.debug('hidden')
+    logger.info('shown')
+
+    expect(errorSpy).toHaveBeenCalledTimes(1)
+    expect(errorSpy).toHaveBeenCalledWith('[aicouncil] info: shown')
+  })
+
+  it('should write debug lines with their fields once enabled', () => {
+    const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+    setLogLevel('debug')
+
+    logger.debug('model call', { participant: 'Kimi', latencyMs: 120, skipped: undefined })
+
+    expect(errorSpy).toHaveBeenCalledWith('[aicouncil] debug: model call participant="Kimi" latencyMs=120')
+  })
+
+  it('should redact API keys from messages and field values', () => {
+    const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+
+    logger.error('call failed for sk-abcdefgh12345678', { error: 'Authorization: Bearer abcdefgh12345678' })
+
+    expect(errorSpy).toHaveBeenCalledWith(
+      '[aicouncil] error: call failed for [REDACTED] error="Authorization: Bearer [REDACTED]"'
+    )
+  })
+
+  it('should send warnings to console.warn and silence everything when silent', () => {
+    const warnSpy = vi.spyOn(console, 'warn').mockImplementation(() => {})
+    const errorSpy = vi.spyOn(console, 'error').mockImplementation(() => {})
+
+    logger.warn('careful')
+    setLogLevel('silent')
+    logger.error('ignored')
+
+    expect(warnSpy).toHaveBeenCalledWith('[aicouncil] warn: careful')
+    expect(errorSpy).not.toHaveBeenCalled()
+  })
+})
@@ -2,11 +2,42 @@
  * Utility Functions
  */
 
+import type { JsonSchema, LogLevel } from '../types'
+
+/**
+ * Default ID source: timestamp plus a random suffix
+ */
+function defaultIdGenerator(): string {
+  return `${Date.now()}-${Math.random().toString(36).substring(2, 9)}`
+}
+
+let idGenerator: () => string = defaultIdGenerator
+
 /**
  * Generate a unique ID
  */
 export function generateId(): string {
-  return `${Date.now()}-${Math.random().toString(36).substring(2, 9)}`
+  return idGenerator()
+}
+
+/**
+ * Replace the ID source, e.g. with a sequential one for deterministic tests.
+ * Pass null to restore the default.
+ */
+export function setIdGenerator(generator: (() => string) | null): void {
+  idGenerator = generator ?? defaultIdGenerator
+}
+
+/**
+ * Hash a string (32-bit FNV-1a), returned as hex
+ */
+export function hashString(str: string): string {
+  let hash = 0x811c9dc5
+  for (let i = 0; i < str.length; i++) {
+    hash ^= str.charCodeAt(i)
+    hash = Math.imul(hash, 0x01000193)
+  }
+  return (hash >>> 0).toString(16).padStart(8, '0')
 }
 
 /**
@@ -23,11 +54,21 @@ export function timeout<T>(promise: Promise<T>, ms: number, message?: string): P
   return Promise.race([
     promise,
     new Promise<T>((_, reject) =>
-      setTimeout(() => reject(new Error(message ?? `Timeout after ${ms}ms`)), ms)
+      setTimeout(() => reject(new TimeoutError(message ?? `Timeout after ${ms}ms`)), ms)
     ),
   ])
 }
 
+/**
+ * Error raised when `timeout` gives up waiting
+ */
+export class TimeoutError extends Error {
+  constructor(message: string) {
+    super(message)
+    this.name = 'TimeoutError'
+  }
+}
+
 /**
  * Retry a function with exponential backoff
  */
@@ -38,6 +79,10 @@ export async function retry<T>(
     initialDelay?: number
     maxDelay?: number
     backoffFactor?: number
+    /** Adjust the wait before the next attempt based on the error */
+    delayFor?: (error: Error, delay: number) => number
+    /** Give up at once when this returns false for an error */
+    shouldRetry?: (error: Error) => boolean
   } = {}
 ): Promise<T> {
   const {
@@ -45,6 +90,8 @@ export async function retry<T>(
     initialDelay = 1000,
     maxDelay = 30000,
     backoffFactor = 2,
+    delayFor,
+    shouldRetry,
   } = options
 
   let lastError: Error | undefined
@@ -56,8 +103,12 @@ export async function retry<T>(
     } catch (error) {
       lastError = error instanceof Error ? error : new Error(String(error))
       
+      if (shouldRetry && !shouldRetry(lastError)) {
+        break
+      }
+
       if (attempt < maxRetries) {
-        await sleep(delay)
+        await sleep(delayFor ? delayFor(lastError, delay) : delay)
         delay = Math.min(delay * backoffFactor, maxDelay)
       }
     }
@@ -73,6 +124,16 @@ export function formatDate(date: Date): string {
   return date.toISOString().replace('T', ' ').substring(0, 19)
 }
 
+/**
+ * Roughly estimate how many tokens a text uses
+ *
+ * About four characters per token, which is close enough for budgeting
+ * English and code; CJK text runs higher.
+ */
+export function estimateTokens(text: string): number {
+  return Math.ceil(text.length / 4)
+}
+
 /**
  * Truncate a string to a maximum length
  */
@@ -81,6 +142,119 @@ export function truncate(str: string, maxLength: number, suffix = '...'): string
   return str.substring(0, maxLength - suffix.length) + suffix
 }
 
+/**
+ * Patterns for credentials that should never be surfaced
+ */
+const SECRET_PATTERNS: Array<[RegExp, string]> = [
+  [/\bsk-[A-Za-z0-9_-]{8,}/g, '[REDACTED]'],
+  [/\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+/g, '[REDACTED]'],
+  [/(Bearer\s+)[A-Za-z0-9._~+/=-]{8,}/gi, '$1[REDACTED]'],
+]
+
+/**
+ * Scrub known secrets and common API key patterns from a string
+ */
+export function redactSecrets(text: string, secrets: Array<string | undefined> = []): string {
+  let result = text
+
+  for (const secret of secrets) {
+    // Very short values would redact ordinary words
+    if (secret && secret.length >= 8) {
+      result = result.split(secret).join('[REDACTED]')
+    }
+  }
+
+  for (const [pattern, replacement] of SECRET_PATTERNS) {
+    result = result.replace(pattern, replacement)
+  }
+
+  return result
+}
+
+/**
+ * Validate a value against a JSON schema
+ *
+ * Supports type, properties, required, items and enum, which covers the
+ * schemas providers accept for structured output. Returns a description of
+ * the first mismatch, or null when the value conforms.
+ */
+export function validateJsonSchema(value: unknown, schema: JsonSchema, path = '$'): string | null {
+  if (schema.enum && !schema.enum.some(v => JSON.stringify(v) === JSON.stringify(value))) {
+    return `${path} must be one of ${JSON.stringify(schema.enum)}`
+  }
+
+  switch (schema.type) {
+    case 'object':
+      if (!isObject(value)) return `${path} must be an object`
+      for (const key of schema.required ?? []) {
+        if (!(key in value)) return `${path}.${key} is required`
+      }
+      for (const [key, propSchema] of Object.entries(schema.properties ?? {})) {
+        if (key in value) {
+          const error = validateJsonSchema(value[key], propSchema, `${path}.${key}`)
+          if (error) return error
+        }
+      }
+      return null
+    case 'array':
+      if (!Array.isArray(value)) return `${path} must be an array`
+      if (schema.items) {
+        for (let i = 0; i < value.length; i++) {
+          const error = validateJsonSchema(value[i], schema.items, `${path}[${i}]`)
+          if (error) return error
+        }
+      }
+      return null
+    case 'integer':
+      return Number.isInteger(value) ? null : `${path} must be an integer`
+    case 'null':
+      return value === null ? null : `${path} must be null`
+    case undefined:
+      return null
+    default:
+      return typeof value === schema.type ? null : `${path} must be a ${schema.type}`
+  }
+}
+
+/**
+ * JSON schema types understood by validateJsonSchema
+ */
+const JSON_SCHEMA_TYPES = ['object', 'array', 'string', 'number', 'integer', 'boolean', 'null']
+
+/**
+ * Check that a value is a JSON schema validateJsonSchema can apply
+ *
+ * Unknown keywords are allowed and ignored. Returns a description of the
+ * first malformed keyword, or null when the schema is usable.
+ */
+export function jsonSchemaError(schema: unknown, path = '$'): string | null {
+  if (!isObject(schema)) return `${path} must be a schema object`
+
+  if (schema.type !== undefined && !JSON_SCHEMA_TYPES.includes(schema.type as string)) {
+    return `${path}.type must be one of ${JSON_SCHEMA_TYPES.join(', ')}`
+  }
+  if (schema.properties !== undefined) {
+    if (!isObject(schema.properties)) return `${path}.properties must be an object`
+    for (const [key, propSchema] of Object.entries(schema.properties)) {
+      const error = jsonSchemaError(propSchema, `${path}.properties.${key}`)
+      if (error) return error
+    }
+  }
+  if (schema.required !== undefined
+    && !(Array.isArray(schema.required) && schema.required.every(key => typeof key === 'string'))) {
+    return `${path}.required must be an array of property names`
+  }
+  if (schema.items !== undefined) {
+    const error = jsonSchemaError(schema.items, `${path}.items`)
+    if (error) return error
+  }
+  if (schema.enum !== undefined && !Array.isArray(schema.enum)) {
+    return `${path}.enum must be an array`
+  }
+
+  return null
+}
+
 /**
  * Deep clone an object
  */
@@ -147,7 +321,7 @@ export function createEventEmitter<T extends Record<string, unknown[]>>() {
         try {
           handler(...args)
         } catch (error) {
-          console.error(`Error in event handler for "${String(event)}":`, error)
+          logger.error(`Error in event handler for "${String(event)}"`, { error: String(error) })
         }
       })
     },
@@ -163,5 +337,64 @@ export function createEventEmitter<T extends Record<string, unknown[]>>() {
     clear(): void {
       listeners.clear()
     },
+
+    listenerCount<K extends keyof T>(event: K): number {
+      return listeners.get(event)?.size ?? 0
+    },
   }
 }
+
+const LOG_LEVELS: LogLevel[] = ['debug', 'info', 'warn', 'error', 'silent']
+
+let logLevel: LogLevel = 'info'
+
+/**
+ * Set the lowest level the logger writes
+ */
+export function setLogLevel(level: LogLevel): void {
+  logLevel = level
+}
+
+/**
+ * Get the lowest level the logger writes
+ */
+export function getLogLevel(): LogLevel {
+  return logLevel
+}
+
+/**
+ * Write a log line if its level is enabled
+ *
+ * Lines go to stderr (warnings through console.warn), with fields
+ * appended as key=value pairs. The whole line, fields included, is
+ * scrubbed of common API key patterns.
+ */
+function log(level: Exclude<LogLevel, 'silent'>, message: string, fields?: Record<string, unknown>): void {
+  if (LOG_LEVELS.indexOf(level) < LOG_LEVELS.indexOf(logLevel)) {
+    return
+  }
+
+  const suffix = fields
+    ? Object.entries(fields)
+        .filter(([, value]) => value !== undefined)
+        .map(([key, value]) => ` ${key}=${typeof value === 'string' ? JSON.stringify(value) : String(value)}`)
+        .join('')
+    : ''
+  const line = redactSecrets(`[aicouncil] ${level}: ${message}${suffix}`)
+
+  if (level === 'warn') {
+    console.warn(line)
+  } else {
+    console.error(line)
+  }
+}
+
+/**
+ * Leveled diagnostics logger
+ */
+export const logger = {
+  debug: (message: string, fields?: Record<string, unknown>) => log('debug', message, fields),
+  info: (message: string, fields?: Record<string, unknown>) => log('info', message, fields),
+  warn: (message: string, fields?: Record<string, unknown>) => log('warn', message, fields),
+  error: (message: string, fields?: Record<string, unknown>) => log('error', message, fields),
+}